@@ -2,22 +2,41 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
+	"encoding/base64"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	_ "github.com/lib/pq"
 
 	playerhttp "players_service/internal/delivery/http/player"
+	"players_service/internal/domain/auth"
+	"players_service/internal/domain/player"
 	"players_service/internal/infra/clock"
+	"players_service/internal/infra/geoip"
+	"players_service/internal/infra/notifier"
+	"players_service/internal/infra/oauth"
+	"players_service/internal/infra/password"
 	"players_service/internal/infra/postgres"
+	"players_service/internal/infra/publisher"
+	"players_service/internal/infra/storage"
+	"players_service/internal/infra/token"
+	authpg "players_service/internal/repository/auth/postgres"
 	outboxpg "players_service/internal/repository/outbox/postgres"
 	playerpg "players_service/internal/repository/player/postgres"
+	verificationpg "players_service/internal/repository/verification/postgres"
+	authuc "players_service/internal/usecase/auth"
+	outboxuc "players_service/internal/usecase/outbox"
 	playeruc "players_service/internal/usecase/player"
+	verificationuc "players_service/internal/usecase/verification"
 )
 
 func main() {
@@ -25,6 +44,24 @@ func main() {
 	httpPort := getenv("APP_HTTP_PORT", "8080")
 	pgDSN := buildPostgresDSN()
 
+	var playerOpts []playeruc.Option
+	if spec := os.Getenv("STATUS_TRANSITIONS"); spec != "" {
+		rules, err := player.ParseTransitionRules(spec)
+		if err != nil {
+			log.Fatalf("bad STATUS_TRANSITIONS: %v", err)
+		}
+		playerOpts = append(playerOpts, playeruc.WithTransitionRules(rules))
+	}
+	jurisdiction := loadJurisdiction()
+	playerOpts = append(playerOpts, playeruc.WithJurisdictionPolicy(jurisdiction))
+	if spec := os.Getenv("KYC_RULES"); spec != "" {
+		rules, err := player.ParseKYCRules(spec)
+		if err != nil {
+			log.Fatalf("bad KYC_RULES: %v", err)
+		}
+		playerOpts = append(playerOpts, playeruc.WithKYCRules(rules))
+	}
+
 	// ===== db =====
 	db, err := sql.Open("postgres", pgDSN)
 	if err != nil {
@@ -36,25 +73,161 @@ func main() {
 		log.Fatalf("db ping error: %v", err)
 	}
 
+	// ===== migrations =====
+	if len(os.Args) > 1 {
+		if os.Args[1] != "migrate" {
+			log.Fatalf("unknown command %q", os.Args[1])
+		}
+		runMigrate(db, os.Args[2:])
+		return
+	}
+	if getenv("APP_MIGRATE_ON_START", "false") == "true" {
+		migrateOnStart(db)
+	}
+
 	// ===== infra =====
 	uow := postgres.NewUnitOfWork(db)
 
 	playerRepo := playerpg.New(db)
 	eventRepo := playerpg.NewEvents(db)
 	outboxRepo := outboxpg.New(db)
+	sessionRepo := authpg.NewSessions(db)
+
+	documentFiles, err := storage.NewLocal(getenv("DOCUMENT_STORAGE_DIR", "documents"))
+	if err != nil {
+		log.Fatalf("document storage: %v", err)
+	}
+
+	notify, closeNotify := buildNotifier()
+	defer closeNotify()
+
+	playerOpts = append(playerOpts, playeruc.WithSessionRevoker(sessionRepo))
+	// GeoLite2/GeoIP2 Country mmdb from MaxMind, kept up to date by
+	// geoipupdate; the image does not bundle it
+	if path := os.Getenv("GEOIP_DB_PATH"); path != "" {
+		geo, err := geoip.Open(path)
+		if err != nil {
+			log.Fatalf("geoip: %v", err)
+		}
+		defer geo.Close()
+		playerOpts = append(playerOpts, playeruc.WithGeoLocator(geo))
+	} else if len(jurisdiction.Restricted) > 0 || jurisdiction.RejectUnknownIPCountry {
+		log.Fatalf("GEOIP_DB_PATH is required with RESTRICTED_COUNTRIES or REJECT_UNKNOWN_IP_COUNTRY")
+	} else {
+		log.Printf("GEOIP_DB_PATH is not set, registration IPs are not geolocated")
+	}
 
 	// ===== usecase =====
 	playerService := playeruc.New(
 		uow,
 		playerRepo,
 		eventRepo,
+		playerpg.NewDocuments(db),
+		documentFiles,
 		outboxRepo,
 		clock.New(),
+		playerOpts...,
+	)
+
+	codeService := verificationuc.New(uow, verificationpg.New(db), notify, clock.New(), verificationuc.Config{
+		Length:         getenvInt("VERIFICATION_CODE_LENGTH", 6),
+		TTL:            getenvDuration("VERIFICATION_CODE_TTL", 10*time.Minute),
+		MaxAttempts:    getenvInt("VERIFICATION_MAX_ATTEMPTS", 5),
+		ResendCooldown: getenvDuration("VERIFICATION_RESEND_COOLDOWN", time.Minute),
+		Window:         getenvDuration("VERIFICATION_RATE_WINDOW", time.Hour),
+		MaxPerDest:     getenvInt("VERIFICATION_MAX_PER_DESTINATION", 5),
+		MaxPerIP:       getenvInt("VERIFICATION_MAX_PER_IP", 20),
+		Secret:         loadSecret("VERIFICATION_SECRET"),
+	})
+
+	authService, err := authuc.New(authuc.Deps{
+		UoW:       uow,
+		Players:   playerRepo,
+		Registrar: playerService,
+		Contacts:  playerService,
+		Statuses:  playerService,
+		Passwords: authpg.NewPasswords(db),
+		Sessions:  sessionRepo,
+		Resets:    authpg.NewResets(db),
+		Audit:     authpg.NewPasswordAudit(db),
+		Social:    authpg.NewSocial(db),
+		Failures:  authpg.NewFailures(db),
+		Logins:    authpg.NewLogins(db),
+		Verifiers: buildProviderVerifiers(),
+		Hasher:    password.NewArgon2id(password.DefaultParams),
+		Tokens:    token.NewAccessTokens(loadKeys("AUTH_SIGNING_KEYS", devMode())),
+		// to rotate, put the new key first and keep the old one listed
+		// until INTEGRATION_TOKEN_TTL has passed; required even in dev mode,
+		// rotation only works with kids that survive a restart
+		IntTokens: token.NewIntegrationTokens(loadKeys("INTEGRATION_SIGNING_KEYS", false)),
+		Codes:     codeService,
+		Clock:     clock.New(),
+	}, authuc.Config{
+		AccessTTL:      getenvDuration("AUTH_ACCESS_TTL", 15*time.Minute),
+		RefreshTTL:     getenvDuration("AUTH_REFRESH_TTL", 30*24*time.Hour),
+		ResetTTL:       getenvDuration("AUTH_RESET_TTL", 15*time.Minute),
+		IntegrationTTL: getenvDuration("INTEGRATION_TOKEN_TTL", 5*time.Minute),
+		Lockout: authuc.LockoutConfig{
+			Window:       getenvDuration("LOGIN_FAILURE_WINDOW", 15*time.Minute),
+			MaxPerPlayer: getenvInt("LOGIN_MAX_FAILURES_PER_PLAYER", 5),
+			MaxPerIP:     getenvInt("LOGIN_MAX_FAILURES_PER_IP", 20),
+			// 0 keeps the player frozen until an admin unfreezes it
+			UnlockAfter: getenvDuration("LOGIN_UNLOCK_AFTER", 30*time.Minute),
+		},
+	})
+	if err != nil {
+		log.Fatalf("auth init error: %v", err)
+	}
+
+	// ===== workers =====
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+
+	expiry := playeruc.NewStatusExpiryWorker(
+		playerService,
+		getenvDuration("STATUS_EXPIRY_INTERVAL", time.Minute),
+		getenvInt("STATUS_EXPIRY_BATCH", 100),
 	)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		expiry.Run(workersCtx)
+	}()
+
+	pub, closePub := buildPublisher()
+	defer closePub()
+	if pub != nil {
+		relay := outboxuc.NewRelay(outboxRepo, pub, clock.New(), outboxuc.Config{
+			BatchSize:    getenvInt("OUTBOX_BATCH_SIZE", 100),
+			PollInterval: getenvDuration("OUTBOX_POLL_INTERVAL", time.Second),
+			Lease:        getenvDuration("OUTBOX_LEASE", 5*time.Minute),
+			BackoffBase:  getenvDuration("OUTBOX_BACKOFF_BASE", time.Second),
+			BackoffMax:   getenvDuration("OUTBOX_BACKOFF_MAX", 10*time.Minute),
+		})
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			relay.Run(workersCtx)
+		}()
+	}
 
 	// ===== http =====
-	handler := playerhttp.New(playerService)
-	router := playerhttp.Routes(handler)
+	// only these peers may tell the client address in X-Forwarded-For
+	trustedProxies, err := playerhttp.ParseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		log.Fatalf("bad TRUSTED_PROXIES: %v", err)
+	}
+	// game providers calling /integration/verify, "provider:apikey,..."
+	integrationClients, err := playerhttp.ParseIntegrationClients(os.Getenv("INTEGRATION_CLIENT_KEYS"))
+	if err != nil {
+		log.Fatalf("bad INTEGRATION_CLIENT_KEYS: %v", err)
+	}
+	router := playerhttp.Routes(playerhttp.Handlers{
+		Players:            playerhttp.New(playerService),
+		Auth:               playerhttp.NewAuth(authService),
+		TrustedProxies:     trustedProxies,
+		IntegrationClients: integrationClients,
+	})
 
 	server := &http.Server{
 		Addr:              ":" + httpPort,
@@ -82,11 +255,202 @@ func main() {
 		log.Printf("shutdown error: %v", err)
 	}
 
+	stopWorkers()
+	workers.Wait()
+
 	log.Println("bye")
 }
 
 // --- helpers ---
 
+// loadKeys reads signing keys from env ("kid:base64secret,...", current first).
+// Tokens signed by one instance must verify on all of them and outlive a
+// restart, so an unset env is fatal unless ephemeralOK: then a random key is
+// used and its tokens die with the process.
+func loadKeys(env string, ephemeralOK bool) token.KeyProvider {
+	spec := os.Getenv(env)
+	if spec == "" {
+		if !ephemeralOK {
+			log.Fatalf("%s is not set", env)
+		}
+		k, err := token.RandomKey("ephemeral")
+		if err != nil {
+			log.Fatalf("%s: %v", env, err)
+		}
+		log.Printf("%s is not set, using an ephemeral signing key", env)
+		return token.NewStaticKeys(k)
+	}
+	keys, err := token.ParseKeys(spec)
+	if err != nil {
+		log.Fatalf("bad %s: %v", env, err)
+	}
+	return keys
+}
+
+// loadSecret reads a base64 secret from env. Every instance must share it,
+// so it is required; only APP_DEV_MODE falls back to a random one.
+func loadSecret(env string) []byte {
+	spec := os.Getenv(env)
+	if spec == "" {
+		if !devMode() {
+			log.Fatalf("%s is not set", env)
+		}
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			log.Fatalf("%s: %v", env, err)
+		}
+		log.Printf("%s is not set, using an ephemeral secret", env)
+		return b
+	}
+	b, err := base64.StdEncoding.DecodeString(spec)
+	if err != nil {
+		log.Fatalf("bad %s: %v", env, err)
+	}
+	return b
+}
+
+// loadJurisdiction reads the country rules: MIN_AGE (default 18),
+// MIN_AGE_BY_COUNTRY ("US:21,EE:21"), BIRTH_DATE_REQUIRED_COUNTRIES ("GB,DE"),
+// RESTRICTED_COUNTRIES ("US,FR") and REJECT_UNKNOWN_IP_COUNTRY (true by
+// default when some countries are restricted).
+func loadJurisdiction() player.JurisdictionPolicy {
+	j := player.DefaultJurisdictionPolicy()
+	j.DefaultMinAge = getenvInt("MIN_AGE", j.DefaultMinAge)
+
+	minAges, err := player.ParseMinAges(os.Getenv("MIN_AGE_BY_COUNTRY"))
+	if err != nil {
+		log.Fatalf("bad MIN_AGE_BY_COUNTRY: %v", err)
+	}
+	j.MinAge = minAges
+
+	required, err := player.ParseCountries(os.Getenv("BIRTH_DATE_REQUIRED_COUNTRIES"))
+	if err != nil {
+		log.Fatalf("bad BIRTH_DATE_REQUIRED_COUNTRIES: %v", err)
+	}
+	j.BirthDateRequired = required
+
+	restricted, err := player.ParseCountries(os.Getenv("RESTRICTED_COUNTRIES"))
+	if err != nil {
+		log.Fatalf("bad RESTRICTED_COUNTRIES: %v", err)
+	}
+	j.Restricted = restricted
+
+	j.RejectUnknownIPCountry = len(restricted) > 0
+	if v := os.Getenv("REJECT_UNKNOWN_IP_COUNTRY"); v != "" {
+		reject, err := strconv.ParseBool(v)
+		if err != nil {
+			log.Fatalf("bad REJECT_UNKNOWN_IP_COUNTRY: %v", err)
+		}
+		j.RejectUnknownIPCountry = reject
+	}
+	return j
+}
+
+// buildProviderVerifiers enables social login per provider: google with
+// GOOGLE_CLIENT_IDS (the OAuth client ids its ID tokens are issued to),
+// telegram with TELEGRAM_BOT_TOKEN (login widget data no older than
+// TELEGRAM_AUTH_MAX_AGE). Providers left unconfigured are off.
+//
+// OAUTH_STUB_PROVIDERS ("google,telegram") puts the stub IdP, signing with
+// OAUTH_STUB_KEYS, in place of the real ones. Anybody holding those keys can
+// log in as anyone, so it is refused unless APP_DEV_MODE=true.
+func buildProviderVerifiers() map[auth.Provider]authuc.ProviderVerifier {
+	out := map[auth.Provider]authuc.ProviderVerifier{}
+
+	if spec := os.Getenv("GOOGLE_CLIENT_IDS"); spec != "" {
+		var ids []string
+		for _, id := range strings.Split(spec, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+		client := &http.Client{Timeout: 10 * time.Second}
+		v, err := oauth.NewOIDC(auth.ProviderGoogle, oauth.GoogleConfig(ids), client, clock.New())
+		if err != nil {
+			log.Fatalf("bad GOOGLE_CLIENT_IDS: %v", err)
+		}
+		out[auth.ProviderGoogle] = v
+	}
+	if botToken := os.Getenv("TELEGRAM_BOT_TOKEN"); botToken != "" {
+		v, err := oauth.NewTelegram(botToken, getenvDuration("TELEGRAM_AUTH_MAX_AGE", time.Hour), clock.New())
+		if err != nil {
+			log.Fatalf("telegram login: %v", err)
+		}
+		out[auth.ProviderTelegram] = v
+	}
+
+	spec := os.Getenv("OAUTH_STUB_PROVIDERS")
+	if spec == "" {
+		return out
+	}
+	if !devMode() {
+		log.Fatalf("OAUTH_STUB_PROVIDERS is for development only, set APP_DEV_MODE=true to use it")
+	}
+	keys := loadKeys("OAUTH_STUB_KEYS", true)
+	for _, name := range strings.Split(spec, ",") {
+		prov, err := auth.ParseProvider(name)
+		if err != nil {
+			log.Fatalf("bad OAUTH_STUB_PROVIDERS: %v", err)
+		}
+		if _, ok := out[prov]; ok {
+			log.Fatalf("OAUTH_STUB_PROVIDERS: %s already has a real verifier", prov)
+		}
+		log.Printf("social login with %s uses the stub IdP", prov)
+		out[prov] = oauth.NewStub(prov, keys, clock.New())
+	}
+	return out
+}
+
+// buildNotifier picks the code delivery from NOTIFIER: log (default) or file.
+func buildNotifier() (verificationuc.Notifier, func()) {
+	switch kind := getenv("NOTIFIER", "log"); kind {
+	case "log":
+		return notifier.NewLog(), func() {}
+	case "file":
+		n, err := notifier.NewFile(getenv("NOTIFIER_FILE_PATH", "codes.jsonl"))
+		if err != nil {
+			log.Fatalf("file notifier: %v", err)
+		}
+		return n, func() { _ = n.Close() }
+	default:
+		log.Fatalf("unknown NOTIFIER %q", kind)
+		return nil, nil
+	}
+}
+
+// buildPublisher picks the outbox publisher from OUTBOX_PUBLISHER:
+// stdout (default), file, webhook or none (relay disabled).
+func buildPublisher() (outboxuc.Publisher, func()) {
+	switch kind := getenv("OUTBOX_PUBLISHER", "stdout"); kind {
+	case "none":
+		return nil, func() {}
+	case "stdout":
+		return publisher.NewStdout(), func() {}
+	case "file":
+		p, err := publisher.NewFile(getenv("OUTBOX_FILE_PATH", "outbox.jsonl"))
+		if err != nil {
+			log.Fatalf("outbox file publisher: %v", err)
+		}
+		return p, func() { _ = p.Close() }
+	case "webhook":
+		url := os.Getenv("OUTBOX_WEBHOOK_URL")
+		if url == "" {
+			log.Fatalf("OUTBOX_WEBHOOK_URL is required for webhook publisher")
+		}
+		client := &http.Client{Timeout: getenvDuration("OUTBOX_WEBHOOK_TIMEOUT", 10*time.Second)}
+		return publisher.NewWebhook(url, os.Getenv("OUTBOX_WEBHOOK_SECRET"), client), func() {}
+	default:
+		log.Fatalf("unknown OUTBOX_PUBLISHER %q", kind)
+		return nil, nil
+	}
+}
+
+// devMode is APP_DEV_MODE=true: a single local instance, where throwaway
+// secrets and the stub IdP are fine.
+func devMode() bool {
+	return getenv("APP_DEV_MODE", "false") == "true"
+}
+
 func getenv(key, def string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
@@ -94,6 +458,30 @@ func getenv(key, def string) string {
 	return def
 }
 
+func getenvInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Fatalf("bad %s: %v", key, err)
+	}
+	return n
+}
+
+func getenvDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Fatalf("bad %s: %v", key, err)
+	}
+	return d
+}
+
 func buildPostgresDSN() string {
 	host := getenv("POSTGRES_HOST", "localhost")
 	port := getenv("POSTGRES_PORT", "5432")
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"players_service/internal/infra/migrate"
+	"players_service/migrations"
+)
+
+// runMigrate handles `migrate up|down [N]|status`.
+func runMigrate(db *sql.DB, args []string) {
+	m, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		log.Fatalf("migrations load error: %v", err)
+	}
+	ctx := context.Background()
+
+	if len(args) == 0 {
+		log.Fatalf("usage: %s migrate up|down [N]|status", os.Args[0])
+	}
+
+	switch args[0] {
+	case "up":
+		done, err := m.Up(ctx)
+		for _, mg := range done {
+			log.Printf("applied %04d_%s", mg.Version, mg.Name)
+		}
+		if err != nil {
+			log.Fatalf("migrate up: %v", err)
+		}
+		if len(done) == 0 {
+			log.Println("nothing to apply")
+		}
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				log.Fatalf("migrate down: bad step count %q", args[1])
+			}
+		}
+		done, err := m.Down(ctx, steps)
+		for _, mg := range done {
+			log.Printf("reverted %04d_%s", mg.Version, mg.Name)
+		}
+		if err != nil {
+			log.Fatalf("migrate down: %v", err)
+		}
+
+	case "status":
+		st, err := m.Status(ctx)
+		if err != nil {
+			log.Fatalf("migrate status: %v", err)
+		}
+		for _, s := range st {
+			applied := "pending"
+			if !s.AppliedAt.IsZero() {
+				applied = s.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-40s %s\n", s.Version, s.Name, applied)
+		}
+
+	default:
+		log.Fatalf("unknown migrate command %q", args[0])
+	}
+}
+
+func migrateOnStart(db *sql.DB) {
+	m, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		log.Fatalf("migrations load error: %v", err)
+	}
+	done, err := m.Up(context.Background())
+	for _, mg := range done {
+		log.Printf("applied migration %04d_%s", mg.Version, mg.Name)
+	}
+	if err != nil {
+		log.Fatalf("migrate on start: %v", err)
+	}
+}
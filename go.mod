@@ -6,4 +6,8 @@ require (
 	github.com/go-chi/chi/v5 v5.0.12
 	github.com/google/uuid v1.6.0
 	github.com/lib/pq v1.10.9
+	github.com/oschwald/maxminddb-golang v1.13.1
+	golang.org/x/crypto v0.33.0
 )
+
+require golang.org/x/sys v0.30.0 // indirect
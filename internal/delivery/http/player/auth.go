@@ -0,0 +1,522 @@
+package playerhttp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/auth"
+	authuc "players_service/internal/usecase/auth"
+)
+
+type AuthHTTP struct {
+	uc *authuc.Service
+}
+
+func NewAuth(uc *authuc.Service) *AuthHTTP {
+	return &AuthHTTP{uc: uc}
+}
+
+type registerReq struct {
+	Email     string         `json:"email"`
+	Phone     string         `json:"phone"`
+	Password  string         `json:"password"`
+	Code      string         `json:"code"`
+	BirthDate string         `json:"birth_date"` // YYYY-MM-DD, optional
+	Country   string         `json:"country"`
+	Currency  string         `json:"currency"`
+	Locale    string         `json:"locale"`
+	TimeZone  string         `json:"time_zone"`
+	Metadata  map[string]any `json:"metadata"`
+}
+
+func (h *AuthHTTP) Register(w http.ResponseWriter, r *http.Request) {
+	var req registerReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_json")
+		return
+	}
+
+	birth, err := parseDate(req.BirthDate)
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_birth_date")
+		return
+	}
+
+	p, err := h.uc.Register(r.Context(), authuc.RegisterCmd{
+		Email:          req.Email,
+		Phone:          req.Phone,
+		Password:       req.Password,
+		Code:           req.Code,
+		BirthDate:      birth,
+		CountryCode:    req.Country,
+		Currency:       req.Currency,
+		Locale:         req.Locale,
+		TimeZone:       req.TimeZone,
+		RegistrationIP: clientIP(r),
+		Metadata:       req.Metadata,
+	})
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, toPlayerDTO(p))
+}
+
+type sendCodeReq struct {
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+func (h *AuthHTTP) SendRegistrationCode(w http.ResponseWriter, r *http.Request) {
+	var req sendCodeReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_json")
+		return
+	}
+
+	res, err := h.uc.SendRegistrationCode(r.Context(), authuc.SendCodeCmd{
+		Email: req.Email,
+		Phone: req.Phone,
+		IP:    clientIP(r),
+	})
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"codeLength": res.CodeLength})
+}
+
+func (h *AuthHTTP) SendVerifyCode(w http.ResponseWriter, r *http.Request) {
+	var req sendCodeReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_json")
+		return
+	}
+
+	res, err := h.uc.SendVerifyCode(r.Context(), claimsFrom(r.Context()).PlayerID, authuc.SendCodeCmd{
+		Email: req.Email,
+		Phone: req.Phone,
+		IP:    clientIP(r),
+	})
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"codeLength": res.CodeLength})
+}
+
+type verifyContactReq struct {
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+	Code  string `json:"code"`
+}
+
+// VerifyContact confirms the email or the phone, despite the path name.
+func (h *AuthHTTP) VerifyContact(w http.ResponseWriter, r *http.Request) {
+	var req verifyContactReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_json")
+		return
+	}
+
+	p, err := h.uc.VerifyContact(r.Context(), claimsFrom(r.Context()).PlayerID, authuc.VerifyContactCmd{
+		Email: req.Email,
+		Phone: req.Phone,
+		Code:  req.Code,
+	})
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toPlayerDTO(p))
+}
+
+// resetPassReq covers the three steps of a reset, told apart by the fields set:
+// email/phone asks for a code, plus code gets a reset token, reset_token plus
+// password sets the password.
+type resetPassReq struct {
+	Email      string `json:"email"`
+	Phone      string `json:"phone"`
+	Code       string `json:"code"`
+	ResetToken string `json:"reset_token"`
+	Password   string `json:"password"`
+}
+
+func (h *AuthHTTP) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req resetPassReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_json")
+		return
+	}
+
+	switch {
+	case req.ResetToken != "" || req.Password != "":
+		err := h.uc.CompletePasswordReset(r.Context(), authuc.ResetCompleteCmd{
+			Token:    req.ResetToken,
+			Password: req.Password,
+			IP:       clientIP(r),
+		})
+		if err != nil {
+			encodeDomainErr(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, true)
+
+	case req.Code != "":
+		tok, err := h.uc.ConfirmPasswordReset(r.Context(), authuc.ResetConfirmCmd{
+			Email: req.Email,
+			Phone: req.Phone,
+			Code:  req.Code,
+			IP:    clientIP(r),
+		})
+		if err != nil {
+			encodeDomainErr(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"reset_token": tok.Token,
+			"expires_at":  fmtTime(tok.ExpiresAt),
+		})
+
+	default:
+		n, err := h.uc.RequestPasswordReset(r.Context(), authuc.ResetRequestCmd{
+			Email: req.Email,
+			Phone: req.Phone,
+			IP:    clientIP(r),
+		})
+		if err != nil {
+			encodeDomainErr(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"codeLength": n})
+	}
+}
+
+type changePassReq struct {
+	Password    string `json:"password"`
+	NewPassword string `json:"newPassword"` // camelCase as in client.yaml
+}
+
+func (h *AuthHTTP) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	var req changePassReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_json")
+		return
+	}
+
+	c := claimsFrom(r.Context())
+	err := h.uc.ChangePassword(r.Context(), authuc.ChangePasswordCmd{
+		PlayerID:    c.PlayerID,
+		SessionID:   c.SessionID,
+		Password:    req.Password,
+		NewPassword: req.NewPassword,
+		IP:          clientIP(r),
+	})
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, true)
+}
+
+// AddCredential sends a code for a new email or phone, or adds it when the
+// request carries the code.
+func (h *AuthHTTP) AddCredential(w http.ResponseWriter, r *http.Request) {
+	var req verifyContactReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_json")
+		return
+	}
+	playerID := claimsFrom(r.Context()).PlayerID
+
+	if req.Code == "" {
+		res, err := h.uc.RequestCredential(r.Context(), playerID, authuc.SendCodeCmd{
+			Email: req.Email,
+			Phone: req.Phone,
+			IP:    clientIP(r),
+		})
+		if err != nil {
+			encodeDomainErr(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"codeLength": res.CodeLength})
+		return
+	}
+
+	c, err := h.uc.ConfirmCredential(r.Context(), playerID, authuc.VerifyContactCmd{
+		Email: req.Email,
+		Phone: req.Phone,
+		Code:  req.Code,
+	})
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toCredentialDTO(c))
+}
+
+type loginReq struct {
+	Login    string `json:"login"` // email or phone
+	Password string `json:"password"`
+}
+
+func (h *AuthHTTP) Login(w http.ResponseWriter, r *http.Request) {
+	var req loginReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_json")
+		return
+	}
+
+	res, err := h.uc.Login(r.Context(), authuc.LoginCmd{
+		Login:     req.Login,
+		Password:  req.Password,
+		IP:        clientIP(r),
+		UserAgent: r.UserAgent(),
+	})
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"player": toPlayerDTO(res.Player),
+		"tokens": toTokensDTO(res.Tokens),
+	})
+}
+
+type refreshReq struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (h *AuthHTTP) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req refreshReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_json")
+		return
+	}
+
+	tokens, err := h.uc.Refresh(r.Context(), authuc.RefreshCmd{
+		RefreshToken: req.RefreshToken,
+		IP:           clientIP(r),
+		UserAgent:    r.UserAgent(),
+	})
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toTokensDTO(tokens))
+}
+
+func (h *AuthHTTP) Logout(w http.ResponseWriter, r *http.Request) {
+	c := claimsFrom(r.Context())
+
+	if err := h.uc.Logout(r.Context(), c.SessionID); err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, true)
+}
+
+// Kick takes a plain JSON array of player ids, as in admin.yaml.
+func (h *AuthHTTP) Kick(w http.ResponseWriter, r *http.Request) {
+	var req []string
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_json")
+		return
+	}
+
+	ids := make([]uuid.UUID, 0, len(req))
+	for _, s := range req {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			writeErr(w, http.StatusBadRequest, "bad_id")
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	n, err := h.uc.Kick(r.Context(), ids)
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"revoked_sessions": n})
+}
+
+func (h *AuthHTTP) ListLogins(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_id")
+		return
+	}
+	qs := r.URL.Query()
+	limit, err := queryInt(qs.Get("limit"))
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_limit")
+		return
+	}
+
+	page, err := h.uc.ListLogins(r.Context(), authuc.ListLoginsQuery{
+		PlayerID: id,
+		Cursor:   qs.Get("cursor"),
+		Limit:    limit,
+	})
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	items := make([]map[string]any, 0, len(page.Items))
+	for _, l := range page.Items {
+		items = append(items, map[string]any{
+			"id":         l.ID.String(),
+			"ip":         fmtIP(l.IP),
+			"user_agent": l.UserAgent,
+			"method":     string(l.Method),
+			"outcome":    string(l.Outcome),
+			"created_at": fmtTime(l.CreatedAt),
+		})
+	}
+
+	var next any
+	if page.NextCursor != "" {
+		next = page.NextCursor
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"items":       items,
+		"next_cursor": next,
+	})
+}
+
+// SharedIPs lists other players seen on the IPs of the player, for anti-fraud.
+func (h *AuthHTTP) SharedIPs(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_id")
+		return
+	}
+	limit, err := queryInt(r.URL.Query().Get("limit"))
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_limit")
+		return
+	}
+
+	found, err := h.uc.SharedIPs(r.Context(), id, limit)
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	items := make([]map[string]any, 0, len(found))
+	for _, s := range found {
+		items = append(items, map[string]any{
+			"player_id":     s.PlayerID.String(),
+			"ip":            fmtIP(s.IP),
+			"logins":        s.Logins,
+			"last_login_at": fmtTime(s.LastLoginAt),
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+// IntegrationToken mints a token for the game provider given in ?provider=.
+func (h *AuthHTTP) IntegrationToken(w http.ResponseWriter, r *http.Request) {
+	res, err := h.uc.IssueIntegrationToken(r.Context(), claimsFrom(r.Context()).PlayerID, r.URL.Query().Get("provider"))
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"token":      res.Token,
+		"expires_at": fmtTime(res.Claims.ExpiresAt),
+	})
+}
+
+type verifyIntegrationReq struct {
+	Provider string `json:"provider"` // optional, must be the calling provider
+	Token    string `json:"token"`
+}
+
+// VerifyIntegrationToken is the server-to-server check used by game providers.
+// Only tokens minted for the calling provider are accepted.
+func (h *AuthHTTP) VerifyIntegrationToken(w http.ResponseWriter, r *http.Request) {
+	var req verifyIntegrationReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_json")
+		return
+	}
+	caller := integrationClientFrom(r.Context())
+	if req.Provider != "" && !strings.EqualFold(strings.TrimSpace(req.Provider), caller) {
+		writeErr(w, http.StatusForbidden, "forbidden")
+		return
+	}
+
+	res, err := h.uc.VerifyIntegrationToken(r.Context(), caller, req.Token)
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"player_id":  res.Player.ID.String(),
+		"currency":   res.Claims.Currency,
+		"provider":   res.Claims.Provider,
+		"status":     res.Player.Status.String(),
+		"country":    res.Player.Address.CountryCode,
+		"expires_at": fmtTime(res.Claims.ExpiresAt),
+	})
+}
+
+// --- authentication middleware ---
+
+type claimsKey struct{}
+
+// RequirePlayer rejects requests without a valid "Authorization: Bearer" access token.
+func (h *AuthHTTP) RequirePlayer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || strings.TrimSpace(raw) == "" {
+			writeErr(w, http.StatusUnauthorized, "unauthenticated")
+			return
+		}
+
+		c, err := h.uc.Authenticate(r.Context(), strings.TrimSpace(raw))
+		if err != nil {
+			encodeDomainErr(w, err)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), claimsKey{}, c)))
+	})
+}
+
+// claimsFrom is only valid behind RequirePlayer.
+func claimsFrom(ctx context.Context) auth.AccessClaims {
+	c, _ := ctx.Value(claimsKey{}).(auth.AccessClaims)
+	return c
+}
+
+func toTokensDTO(t auth.Tokens) map[string]any {
+	return map[string]any{
+		"access_token":       t.AccessToken,
+		"access_expires_at":  fmtTime(t.AccessExpiresAt),
+		"refresh_token":      t.RefreshToken,
+		"refresh_expires_at": fmtTime(t.RefreshExpiresAt),
+		"token_type":         "Bearer",
+	}
+}
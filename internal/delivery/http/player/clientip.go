@@ -0,0 +1,106 @@
+package playerhttp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ParseTrustedProxies reads a comma separated list of CIDRs or single
+// addresses, e.g. "10.0.0.0/8,192.168.1.10".
+func ParseTrustedProxies(spec string) ([]*net.IPNet, error) {
+	var out []*net.IPNet
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+			if ip == nil {
+				return nil, fmt.Errorf("bad trusted proxy %q", item)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			out = append(out, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, fmt.Errorf("bad trusted proxy %q: %w", item, err)
+		}
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+type clientIPKey struct{}
+
+// resolveClientIP finds the client address once per request, see clientAddr.
+func resolveClientIP(trusted []*net.IPNet) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := clientAddr(r, trusted)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientIPKey{}, ip)))
+		})
+	}
+}
+
+// clientIP is the address resolved by the router, the peer address otherwise.
+func clientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey{}).(string); ok {
+		return ip
+	}
+	return peerIP(r)
+}
+
+// clientAddr is the peer address, unless the peer is a trusted proxy: then
+// X-Forwarded-For is read from the right and the first hop that is not a
+// trusted proxy wins. Everything left of it is set by the client and ignored.
+func clientAddr(r *http.Request, trusted []*net.IPNet) string {
+	addr := peerIP(r)
+	if !isTrusted(net.ParseIP(addr), trusted) {
+		return addr
+	}
+
+	var hops []string
+	for _, h := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(h, ",")...)
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(hops[i]))
+		if ip == nil {
+			// garbage from the client side, the last good hop is all we know
+			return addr
+		}
+		addr = ip.String()
+		if !isTrusted(ip, trusted) {
+			return addr
+		}
+	}
+	return addr
+}
+
+func peerIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func isTrusted(ip net.IP, trusted []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+	for _, n := range trusted {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
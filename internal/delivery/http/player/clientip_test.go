@@ -0,0 +1,49 @@
+package playerhttp
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientAddr(t *testing.T) {
+	trusted, err := ParseTrustedProxies("10.0.0.0/8, 192.168.1.10, fd00::/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		remote string
+		xff    []string
+		want   string
+	}{
+		{"no proxy", "203.0.113.7:5000", nil, "203.0.113.7"},
+		{"untrusted peer, spoofed header", "203.0.113.7:5000", []string{"1.1.1.1"}, "203.0.113.7"},
+		{"trusted peer without header", "10.0.0.2:5000", nil, "10.0.0.2"},
+		{"trusted peer", "10.0.0.2:5000", []string{"198.51.100.4"}, "198.51.100.4"},
+		{"client prepends a fake hop", "10.0.0.2:5000", []string{"1.1.1.1, 198.51.100.4"}, "198.51.100.4"},
+		{"chain of trusted proxies", "10.0.0.2:5000", []string{"1.1.1.1, 198.51.100.4, 192.168.1.10, 10.1.2.3"}, "198.51.100.4"},
+		{"several headers", "10.0.0.2:5000", []string{"1.1.1.1", "198.51.100.4, 10.1.2.3"}, "198.51.100.4"},
+		{"garbage hop", "10.0.0.2:5000", []string{"nonsense, 10.1.2.3"}, "10.1.2.3"},
+		{"only trusted hops", "10.0.0.2:5000", []string{"10.9.9.9"}, "10.9.9.9"},
+		{"ipv6 trusted peer", "[fd00::1]:5000", []string{"2001:db8::5"}, "2001:db8::5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remote
+			for _, h := range tt.xff {
+				r.Header.Add("X-Forwarded-For", h)
+			}
+			if got := clientAddr(r, trusted); got != tt.want {
+				t.Fatalf("clientAddr = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxiesRejectsGarbage(t *testing.T) {
+	if _, err := ParseTrustedProxies("10.0.0.0/8,proxy.local"); err == nil {
+		t.Fatal("want error")
+	}
+}
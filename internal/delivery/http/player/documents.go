@@ -0,0 +1,212 @@
+package playerhttp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/player"
+	playeruc "players_service/internal/usecase/player"
+)
+
+const maxDocumentSize = 10 << 20
+
+// UploadMyDocument stores the "file" part of a multipart form. The returned
+// url is then submitted with the document type to SubmitMyDocument.
+func (h *HTTP) UploadMyDocument(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxDocumentSize+1<<10)
+	mr, err := r.MultipartReader()
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_multipart")
+		return
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			writeErr(w, http.StatusBadRequest, "file_required")
+			return
+		}
+		if err != nil {
+			writeErr(w, http.StatusBadRequest, "bad_multipart")
+			return
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+
+		body := bufio.NewReaderSize(&sizeLimitReader{r: part, left: maxDocumentSize}, 512)
+		head, _ := body.Peek(512)
+
+		ref, err := h.uc.UploadDocumentFile(r.Context(), claimsFrom(r.Context()).PlayerID, http.DetectContentType(head), body)
+		var tooLarge *http.MaxBytesError
+		if errors.Is(err, errFileTooLarge) || errors.As(err, &tooLarge) {
+			writeErr(w, http.StatusRequestEntityTooLarge, "file_too_large")
+			return
+		}
+		if err != nil {
+			encodeDomainErr(w, err)
+			return
+		}
+		writeJSON(w, http.StatusCreated, map[string]any{"url": ref})
+		return
+	}
+}
+
+var errFileTooLarge = errors.New("file too large")
+
+// sizeLimitReader fails with errFileTooLarge instead of silently truncating,
+// so the storage drops the partial file.
+type sizeLimitReader struct {
+	r    io.Reader
+	left int64
+}
+
+func (l *sizeLimitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.left -= int64(n)
+	if l.left < 0 {
+		return n, errFileTooLarge
+	}
+	return n, err
+}
+
+type submitDocumentReq struct {
+	Type string `json:"type"`
+	URL  string `json:"url"` // from UploadMyDocument
+}
+
+func (h *HTTP) SubmitMyDocument(w http.ResponseWriter, r *http.Request) {
+	var req submitDocumentReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_json")
+		return
+	}
+
+	d, err := h.uc.SubmitDocument(r.Context(), playeruc.SubmitDocumentCmd{
+		PlayerID: claimsFrom(r.Context()).PlayerID,
+		Type:     req.Type,
+		FileRef:  req.URL,
+	})
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toDocumentDTO(d))
+}
+
+func (h *HTTP) ListMyDocuments(w http.ResponseWriter, r *http.Request) {
+	h.listDocuments(w, r, claimsFrom(r.Context()).PlayerID)
+}
+
+func (h *HTTP) ListDocuments(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_id")
+		return
+	}
+	h.listDocuments(w, r, id)
+}
+
+func (h *HTTP) listDocuments(w http.ResponseWriter, r *http.Request, playerID uuid.UUID) {
+	docs, err := h.uc.ListDocuments(r.Context(), playerID)
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	items := make([]map[string]any, 0, len(docs))
+	for _, d := range docs {
+		items = append(items, toDocumentDTO(d))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+type reviewDocumentReq struct {
+	Status string `json:"status"` // approved|rejected
+	Reason string `json:"reason"` // required for rejected
+}
+
+func (h *HTTP) ReviewDocument(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_id")
+		return
+	}
+
+	var req reviewDocumentReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_json")
+		return
+	}
+
+	d, rv, err := h.uc.ReviewDocument(r.Context(), playeruc.ReviewDocumentCmd{
+		DocumentID: id,
+		Status:     req.Status,
+		Reason:     req.Reason,
+		Actor:      player.ActorAdmin,
+	})
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"document": toDocumentDTO(d),
+		"review":   toDocumentReviewDTO(rv),
+	})
+}
+
+// DocumentFile streams the file of a document to a reviewer.
+func (h *HTTP) DocumentFile(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_id")
+		return
+	}
+
+	f, contentType, err := h.uc.OpenDocumentFile(r.Context(), id)
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, f)
+}
+
+func toDocumentDTO(d *player.Document) map[string]any {
+	return map[string]any{
+		"id":         d.ID.String(),
+		"player_id":  d.PlayerID.String(),
+		"type":       d.Type.String(),
+		"url":        d.FileRef,
+		"status":     d.Status.String(),
+		"reason":     d.Reason,
+		"version":    d.Version,
+		"created_at": fmtTime(d.CreatedAt),
+		"updated_at": fmtTime(d.UpdatedAt),
+	}
+}
+
+func toDocumentReviewDTO(rv player.DocumentReview) map[string]any {
+	return map[string]any{
+		"id":          rv.ID.String(),
+		"document_id": rv.DocumentID.String(),
+		"player_id":   rv.PlayerID.String(),
+		"from_status": rv.From.String(),
+		"to_status":   rv.To.String(),
+		"reason":      rv.Reason,
+		"actor_type":  rv.ActorType.String(),
+		"created_at":  fmtTime(rv.CreatedAt),
+	}
+}
@@ -5,13 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 
+	"players_service/internal/domain/auth"
 	"players_service/internal/domain/player"
+	"players_service/internal/domain/verification"
 	playeruc "players_service/internal/usecase/player"
 )
 
@@ -91,6 +94,7 @@ type changeStatusReq struct {
 	ToStatus string `json:"to_status"` // active|blocked|frozen|closed
 	Reason   string `json:"reason"`
 	Actor    string `json:"actor"` // player|administrator|system
+	Until    string `json:"until"` // RFC3339 optional, blocked|frozen only
 }
 
 func (h *HTTP) ChangeStatus(w http.ResponseWriter, r *http.Request) {
@@ -109,11 +113,22 @@ func (h *HTTP) ChangeStatus(w http.ResponseWriter, r *http.Request) {
 
 	actor := parseActor(req.Actor)
 
+	var until time.Time
+	if strings.TrimSpace(req.Until) != "" {
+		t, err := time.Parse(time.RFC3339, req.Until)
+		if err != nil {
+			writeErr(w, http.StatusBadRequest, "bad_until")
+			return
+		}
+		until = t.UTC()
+	}
+
 	p, ev, err := h.uc.ChangeStatus(r.Context(), playeruc.ChangeStatusCmd{
 		PlayerID: id,
 		ToStatus: req.ToStatus,
 		Reason:   req.Reason,
 		Actor:    actor,
+		Until:    until,
 	})
 	if err != nil {
 		encodeDomainErr(w, err)
@@ -126,6 +141,129 @@ func (h *HTTP) ChangeStatus(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+type updateProfileReq struct {
+	Version     int64   `json:"version"`
+	FirstName   *string `json:"first_name"`
+	LastName    *string `json:"last_name"`
+	Phone       *string `json:"phone"`
+	BirthDate   *string `json:"birth_date"` // YYYY-MM-DD, "" clears
+	Gender      *string `json:"gender"`
+	CountryCode *string `json:"country_code"`
+	Locale      *string `json:"locale"`
+	TimeZone    *string `json:"time_zone"`
+}
+
+func (h *HTTP) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_id")
+		return
+	}
+
+	var req updateProfileReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_json")
+		return
+	}
+
+	var birth *time.Time
+	if req.BirthDate != nil {
+		var t time.Time
+		if strings.TrimSpace(*req.BirthDate) != "" {
+			t, err = time.Parse("2006-01-02", *req.BirthDate)
+			if err != nil {
+				writeErr(w, http.StatusBadRequest, "bad_birth_date")
+				return
+			}
+		}
+		birth = &t
+	}
+
+	p, err := h.uc.UpdateProfile(r.Context(), playeruc.UpdateProfileCmd{
+		PlayerID:        id,
+		ExpectedVersion: req.Version,
+		FirstName:       req.FirstName,
+		LastName:        req.LastName,
+		Phone:           req.Phone,
+		BirthDate:       birth,
+		Gender:          req.Gender,
+		CountryCode:     req.CountryCode,
+		Locale:          req.Locale,
+		TimeZone:        req.TimeZone,
+	})
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toPlayerDTO(p))
+}
+
+type bulkChangeStatusReq struct {
+	IDs      []string `json:"ids"`
+	ToStatus string   `json:"to_status"`
+	Reason   string   `json:"reason"`
+	Actor    string   `json:"actor"`
+	Until    string   `json:"until"`
+}
+
+func (h *HTTP) BulkChangeStatus(w http.ResponseWriter, r *http.Request) {
+	var req bulkChangeStatusReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_json")
+		return
+	}
+
+	ids := make([]uuid.UUID, 0, len(req.IDs))
+	for _, s := range req.IDs {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			writeErr(w, http.StatusBadRequest, "bad_id")
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	var until time.Time
+	if strings.TrimSpace(req.Until) != "" {
+		t, err := time.Parse(time.RFC3339, req.Until)
+		if err != nil {
+			writeErr(w, http.StatusBadRequest, "bad_until")
+			return
+		}
+		until = t.UTC()
+	}
+
+	res, err := h.uc.BulkChangeStatus(r.Context(), playeruc.BulkChangeStatusCmd{
+		PlayerIDs: ids,
+		ToStatus:  req.ToStatus,
+		Reason:    req.Reason,
+		Actor:     parseActor(req.Actor),
+		Until:     until,
+	})
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	items := make([]map[string]any, 0, len(res))
+	for _, it := range res {
+		item := map[string]any{
+			"id":     it.PlayerID.String(),
+			"result": it.Result,
+		}
+		if it.Event != nil {
+			item["event"] = toEventDTO(*it.Event)
+		} else {
+			item["detail"] = it.Detail
+		}
+		items = append(items, item)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
 func (h *HTTP) GetPlayer(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := uuid.Parse(idStr)
@@ -143,31 +281,227 @@ func (h *HTTP) GetPlayer(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, toPlayerDTO(p))
 }
 
+func (h *HTTP) ListPlayers(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+
+	offset, err := queryInt(qs.Get("offset"))
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_offset")
+		return
+	}
+	limit, err := queryInt(qs.Get("limit"))
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_limit")
+		return
+	}
+	emailVerified, err := queryBool(qs.Get("emailVerified"))
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_email_verified")
+		return
+	}
+	phoneVerified, err := queryBool(qs.Get("phoneVerified"))
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_phone_verified")
+		return
+	}
+
+	res, err := h.uc.ListPlayers(r.Context(), playeruc.ListPlayersQuery{
+		Offset:        offset,
+		Limit:         limit,
+		Search:        qs.Get("search"),
+		Country:       qs.Get("country"),
+		Currency:      qs.Get("currency"),
+		SortBy:        qs.Get("sortBy"),
+		Order:         qs.Get("order"),
+		EmailVerified: emailVerified,
+		PhoneVerified: phoneVerified,
+		KYCLevel:      qs.Get("kycLevel"),
+	})
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	items := make([]map[string]any, 0, len(res.Items))
+	for _, p := range res.Items {
+		items = append(items, toPlayerDTO(p))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"items": items,
+		"total": res.Total,
+	})
+}
+
+type getInfoReq struct {
+	IDs []string `json:"ids"`
+}
+
+func (h *HTTP) GetPlayers(w http.ResponseWriter, r *http.Request) {
+	var req getInfoReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_json")
+		return
+	}
+
+	ids := make([]uuid.UUID, 0, len(req.IDs))
+	for _, s := range req.IDs {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			writeErr(w, http.StatusBadRequest, "bad_id")
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	res, err := h.uc.GetPlayers(r.Context(), ids)
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	items := make([]map[string]any, 0, len(res.Items))
+	for _, p := range res.Items {
+		items = append(items, toPlayerDTO(p))
+	}
+	notFound := make([]string, 0, len(res.NotFound))
+	for _, id := range res.NotFound {
+		notFound = append(notFound, id.String())
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"items":     items,
+		"not_found": notFound,
+	})
+}
+
+func (h *HTTP) ListStatusEvents(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_id")
+		return
+	}
+
+	qs := r.URL.Query()
+	limit, err := queryInt(qs.Get("limit"))
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_limit")
+		return
+	}
+
+	page, err := h.uc.ListStatusEvents(r.Context(), playeruc.ListStatusEventsQuery{
+		PlayerID:  id,
+		Cursor:    qs.Get("cursor"),
+		Limit:     limit,
+		ActorType: qs.Get("actor_type"),
+		ToStatus:  qs.Get("to_status"),
+	})
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	items := make([]map[string]any, 0, len(page.Items))
+	for _, ev := range page.Items {
+		items = append(items, toEventDTO(ev))
+	}
+
+	var next any
+	if page.NextCursor != "" {
+		next = page.NextCursor
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"items":       items,
+		"next_cursor": next,
+	})
+}
+
 // --- dto/mapping ---
 
 func toPlayerDTO(p *player.Player) map[string]any {
 	return map[string]any{
-		"id":            p.ID.String(),
-		"email":         p.Email,
-		"phone":         p.Phone,
-		"status":        p.Status.String(),
-		"status_reason": p.StatusReason,
+		"id":                p.ID.String(),
+		"email":             p.Email,
+		"email_verified_at": fmtTime(p.EmailVerifiedAt),
+		"phone":             p.Phone,
+		"phone_verified_at": fmtTime(p.PhoneVerifiedAt),
+		"status":            p.Status.String(),
+		"status_reason":     p.StatusReason,
+		"status_until":      fmtTime(p.StatusUntil),
+		"kyc_level":         p.KYCLevel.String(),
+		"self_exclusion": map[string]any{
+			"kind":  p.ExclusionKind.String(),
+			"until": fmtTime(p.ExcludedUntil),
+		},
 		"address": map[string]any{
 			"country_code": p.Address.CountryCode,
 			"locale":       p.Address.Locale,
 			"time_zone":    p.Address.TimeZone,
 		},
-		"first_name":      p.FirstName,
-		"last_name":       p.LastName,
-		"birth_date":      fmtDate(p.BirthDate),
-		"gender":          p.Gender.String(),
-		"registration_ip": fmtIP(p.RegistrationIP),
-		"registered_at":   fmtTime(p.RegisteredAt),
-		"last_login_at":   fmtTime(p.LastLoginAt),
-		"metadata":        p.Metadata,
-		"version":         p.Version,
-		"created_at":      fmtTime(p.CreatedAt),
-		"updated_at":      fmtTime(p.UpdatedAt),
+		"first_name":              p.FirstName,
+		"last_name":               p.LastName,
+		"birth_date":              fmtDate(p.BirthDate),
+		"gender":                  p.Gender.String(),
+		"registration_ip":         fmtIP(p.RegistrationIP),
+		"registration_ip_country": p.RegistrationIPCountry,
+		"registered_at":           fmtTime(p.RegisteredAt),
+		"last_login_at":           fmtTime(p.LastLoginAt),
+		"metadata":                p.Metadata,
+		"version":                 p.Version,
+		"created_at":              fmtTime(p.CreatedAt),
+		"updated_at":              fmtTime(p.UpdatedAt),
+	}
+}
+
+type selfExcludeReq struct {
+	Kind string `json:"kind"` // 24h|6_months|permanent
+}
+
+// SelfExclude excludes the authenticated player. It cannot be undone before
+// it runs out, and it ends the player's sessions, this one included.
+func (h *HTTP) SelfExclude(w http.ResponseWriter, r *http.Request) {
+	var req selfExcludeReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_json")
+		return
+	}
+
+	p, ev, err := h.uc.SelfExclude(r.Context(), claimsFrom(r.Context()).PlayerID, req.Kind)
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"player": toPlayerDTO(p),
+		"event":  toEventDTO(ev),
+	})
+}
+
+// ListMyCredentials lists emails and phones of the authenticated player.
+func (h *HTTP) ListMyCredentials(w http.ResponseWriter, r *http.Request) {
+	items, err := h.uc.ListCredentials(r.Context(), claimsFrom(r.Context()).PlayerID)
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	out := make([]map[string]any, 0, len(items))
+	for _, c := range items {
+		out = append(out, toCredentialDTO(c))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": out})
+}
+
+func toCredentialDTO(c player.Credential) map[string]any {
+	return map[string]any{
+		"kind":        c.Kind.String(),
+		"value":       c.Value,
+		"primary":     c.Primary,
+		"verified_at": fmtTime(c.VerifiedAt),
+		"created_at":  fmtTime(c.CreatedAt),
 	}
 }
 
@@ -179,6 +513,7 @@ func toEventDTO(ev player.PlayerStatusEvent) map[string]any {
 		"to_status":   ev.To.String(),
 		"reason":      ev.Reason,
 		"actor_type":  ev.ActorType.String(),
+		"until":       fmtTime(ev.Until),
 		"created_at":  fmtTime(ev.CreatedAt),
 	}
 }
@@ -206,6 +541,33 @@ func fmtIP(ip any) any {
 	return fmt.Sprintf("%v", ip)
 }
 
+// parseDate reads an optional YYYY-MM-DD date, zero when empty.
+func parseDate(s string) (time.Time, error) {
+	if strings.TrimSpace(s) == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", strings.TrimSpace(s))
+}
+
+func queryInt(s string) (int, error) {
+	if strings.TrimSpace(s) == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(strings.TrimSpace(s))
+}
+
+// queryBool returns nil for an absent parameter.
+func queryBool(s string) (*bool, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
 func parseActor(s string) player.ActorType {
 	switch strings.ToLower(strings.TrimSpace(s)) {
 	case "player":
@@ -223,8 +585,33 @@ func encodeDomainErr(w http.ResponseWriter, err error) {
 	switch {
 	case errors.Is(err, player.ErrNotFound):
 		writeErr(w, http.StatusNotFound, "not_found")
+	case errors.Is(err, auth.ErrInvalidCredentials):
+		writeErr(w, http.StatusUnauthorized, "invalid_credentials")
+	case errors.Is(err, auth.ErrUnauthenticated):
+		writeErr(w, http.StatusUnauthorized, "unauthenticated")
+	case errors.Is(err, auth.ErrInvalidToken):
+		writeErr(w, http.StatusUnauthorized, "invalid_token")
+	case errors.Is(err, auth.ErrWrongPassword):
+		writeErr(w, http.StatusBadRequest, "wrong_password")
+	case errors.Is(err, auth.ErrInvalidResetToken):
+		writeErr(w, http.StatusBadRequest, "invalid_reset_token")
+	case errors.Is(err, verification.ErrTooManyRequests),
+		errors.Is(err, auth.ErrLoginThrottled):
+		writeErr(w, http.StatusTooManyRequests, "too_many_requests")
+	case errors.Is(err, verification.ErrInvalidCode),
+		errors.Is(err, verification.ErrCodeExpired),
+		errors.Is(err, verification.ErrTooManyAttempts):
+		writeErr(w, http.StatusBadRequest, "invalid_code")
 	case errors.Is(err, player.ErrConflict):
 		writeErr(w, http.StatusConflict, "conflict")
+	case errors.Is(err, player.ErrForbidden):
+		writeErr(w, http.StatusForbidden, "forbidden")
+	case errors.Is(err, player.ErrRestrictedJurisdiction):
+		writeErr(w, http.StatusForbidden, "restricted_jurisdiction")
+	case errors.Is(err, player.ErrUnderage):
+		writeErr(w, http.StatusForbidden, "underage")
+	case errors.Is(err, player.ErrBirthDateRequired):
+		writeErr(w, http.StatusBadRequest, "birth_date_required")
 	case errors.Is(err, player.ErrValidation),
 		errors.Is(err, player.ErrInvalidEmail),
 		errors.Is(err, player.ErrInvalidPhone),
@@ -232,7 +619,14 @@ func encodeDomainErr(w http.ResponseWriter, err error) {
 		errors.Is(err, player.ErrInvalidGender),
 		errors.Is(err, player.ErrInvalidCountryCode),
 		errors.Is(err, player.ErrInvalidLocale),
-		errors.Is(err, player.ErrInvalidTimeZone):
+		errors.Is(err, player.ErrInvalidTimeZone),
+		errors.Is(err, player.ErrInvalidActorType),
+		errors.Is(err, player.ErrInvalidDocumentType),
+		errors.Is(err, player.ErrInvalidDocumentStatus),
+		errors.Is(err, player.ErrInvalidKYCLevel),
+		errors.Is(err, player.ErrInvalidExclusionKind),
+		errors.Is(err, auth.ErrWeakPassword),
+		errors.Is(err, verification.ErrInvalidDestination):
 		writeErr(w, http.StatusBadRequest, "validation")
 	default:
 		writeErr(w, http.StatusInternalServerError, "internal")
@@ -0,0 +1,93 @@
+package playerhttp
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"players_service/internal/domain/auth"
+)
+
+// minIntegrationKeyLen keeps guessable keys out of the config.
+const minIntegrationKeyLen = 32
+
+// IntegrationClients are the game providers allowed to call the
+// server-to-server API, each with its own API key.
+type IntegrationClients struct {
+	clients []integrationClient
+}
+
+type integrationClient struct {
+	provider string
+	key      [sha256.Size]byte // sha256 of the API key, compared in constant time
+}
+
+// ParseIntegrationClients reads "provider:apikey,provider2:apikey2". A
+// provider may have several keys while one is being replaced.
+func ParseIntegrationClients(spec string) (IntegrationClients, error) {
+	var out IntegrationClients
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, key, ok := strings.Cut(item, ":")
+		if !ok {
+			return IntegrationClients{}, fmt.Errorf("bad integration client %q, want provider:apikey", name)
+		}
+		prov, err := auth.NormalizeIntegrationProvider(name)
+		if err != nil {
+			return IntegrationClients{}, err
+		}
+		if len(key) < minIntegrationKeyLen {
+			return IntegrationClients{}, fmt.Errorf("integration client %s: api key shorter than %d characters", prov, minIntegrationKeyLen)
+		}
+		c := integrationClient{provider: prov, key: sha256.Sum256([]byte(key))}
+		if _, dup := out.lookup(c.key); dup {
+			return IntegrationClients{}, fmt.Errorf("integration client %s: api key used twice", prov)
+		}
+		out.clients = append(out.clients, c)
+	}
+	return out, nil
+}
+
+func (ic IntegrationClients) lookup(key [sha256.Size]byte) (string, bool) {
+	provider := ""
+	for _, c := range ic.clients {
+		if subtle.ConstantTimeCompare(c.key[:], key[:]) == 1 {
+			provider = c.provider
+		}
+	}
+	return provider, provider != ""
+}
+
+type integrationClientKey struct{}
+
+// requireIntegrationClient rejects requests without a known "X-Api-Key" and
+// passes on which provider made the call.
+func requireIntegrationClient(ic IntegrationClients) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get("X-Api-Key")
+			if key == "" {
+				writeErr(w, http.StatusUnauthorized, "unauthenticated")
+				return
+			}
+			provider, ok := ic.lookup(sha256.Sum256([]byte(key)))
+			if !ok {
+				writeErr(w, http.StatusUnauthorized, "unauthenticated")
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), integrationClientKey{}, provider)))
+		})
+	}
+}
+
+// integrationClientFrom is the provider authenticated by requireIntegrationClient.
+func integrationClientFrom(ctx context.Context) string {
+	p, _ := ctx.Value(integrationClientKey{}).(string)
+	return p
+}
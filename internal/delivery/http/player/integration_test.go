@@ -0,0 +1,61 @@
+package playerhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequireIntegrationClient(t *testing.T) {
+	const (
+		evoKey   = "evolution-0123456789abcdef0123456789"
+		pragKey  = "pragmatic-0123456789abcdef0123456789"
+		pragNext = "pragmatic-next-0123456789abcdef012345"
+	)
+	ic, err := ParseIntegrationClients("Evolution:" + evoKey + ", pragmatic:" + pragKey + ",pragmatic:" + pragNext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := requireIntegrationClient(ic)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(integrationClientFrom(r.Context())))
+	}))
+
+	tests := []struct {
+		key      string
+		code     int
+		provider string
+	}{
+		{evoKey, http.StatusOK, "evolution"},
+		{pragKey, http.StatusOK, "pragmatic"},
+		{pragNext, http.StatusOK, "pragmatic"},
+		{"", http.StatusUnauthorized, ""},
+		{evoKey + "x", http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("POST", "/users/players/integration/verify", nil)
+		if tt.key != "" {
+			r.Header.Set("X-Api-Key", tt.key)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != tt.code {
+			t.Fatalf("key %q: status %d, want %d", tt.key, w.Code, tt.code)
+		}
+		if tt.code == http.StatusOK && w.Body.String() != tt.provider {
+			t.Fatalf("key %q: provider %q, want %q", tt.key, w.Body.String(), tt.provider)
+		}
+	}
+}
+
+func TestParseIntegrationClientsRejectsBadKeys(t *testing.T) {
+	for _, spec := range []string{
+		"evolution",
+		"evolution:short",
+		"evolution:0123456789abcdef0123456789abcdef,pragmatic:0123456789abcdef0123456789abcdef",
+	} {
+		if _, err := ParseIntegrationClients(spec); err == nil {
+			t.Errorf("%q: want an error", strings.Split(spec, ":")[0])
+		}
+	}
+}
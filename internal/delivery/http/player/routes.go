@@ -1,18 +1,81 @@
 package playerhttp
 
 import (
+	"net"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 )
 
-func Routes(h *HTTP) http.Handler {
+type Handlers struct {
+	Players *HTTP
+	Auth    *AuthHTTP
+	// TrustedProxies may set X-Forwarded-For, see ParseTrustedProxies.
+	TrustedProxies []*net.IPNet
+	// IntegrationClients may call the server-to-server API.
+	IntegrationClients IntegrationClients
+}
+
+func Routes(h Handlers) http.Handler {
 	r := chi.NewRouter()
+	r.Use(resolveClientIP(h.TrustedProxies))
 
 	r.Route("/players", func(r chi.Router) {
-		r.Post("/", h.CreatePlayer)
-		r.Post("/{id}/status", h.ChangeStatus)
-		r.Get("/{id}", h.GetPlayer) // TODO: implement query usecase
+		r.Post("/", h.Players.CreatePlayer)
+		r.Post("/status", h.Players.BulkChangeStatus)
+		r.Post("/{id}/status", h.Players.ChangeStatus)
+		r.Get("/{id}/status-events", h.Players.ListStatusEvents)
+		r.Get("/{id}", h.Players.GetPlayer) // TODO: implement query usecase
+	})
+
+	// back-office API, paths follow admin.yaml
+	r.Route("/users/players", func(r chi.Router) {
+		r.Get("/", h.Players.ListPlayers)
+		r.Post("/getInfo", h.Players.GetPlayers)
+		r.Put("/{id}/update", h.Players.UpdateProfile)
+		r.Get("/{id}/logins", h.Auth.ListLogins)
+		r.Get("/{id}/logins/shared-ip", h.Auth.SharedIPs)
+		r.Get("/{id}/documents", h.Players.ListDocuments)
+		r.Patch("/document/{id}", h.Players.ReviewDocument)
+		r.Get("/document/{id}/file", h.Players.DocumentFile)
+		r.Post("/kick", h.Auth.Kick)
+
+		// player API, paths follow client.yaml
+		r.Post("/sendCode/registration", h.Auth.SendRegistrationCode)
+		r.Post("/register", h.Auth.Register)
+		r.Post("/login", h.Auth.Login)
+		r.Post("/refresh", h.Auth.Refresh)
+		r.Post("/resetPass", h.Auth.ResetPassword)
+
+		// server-to-server, called by game providers
+		r.With(requireIntegrationClient(h.IntegrationClients)).
+			Post("/integration/verify", h.Auth.VerifyIntegrationToken)
+
+		r.Group(func(r chi.Router) {
+			r.Use(h.Auth.RequirePlayer)
+			r.Delete("/logout", h.Auth.Logout)
+			r.Post("/sendCode/verify", h.Auth.SendVerifyCode)
+			r.Post("/verifyEmail", h.Auth.VerifyContact)
+			r.Post("/changePass", h.Auth.ChangePassword)
+			r.Post("/addCredential", h.Auth.AddCredential)
+			r.Get("/credentials", h.Players.ListMyCredentials)
+			r.Get("/my-documents", h.Players.ListMyDocuments)
+			r.Post("/my-documents", h.Players.SubmitMyDocument)
+			r.Post("/my-documents/upload", h.Players.UploadMyDocument)
+			r.Post("/selfExclusion", h.Players.SelfExclude)
+			r.Get("/integration/token", h.Auth.IntegrationToken)
+		})
+	})
+
+	r.Route("/users/oauth2", func(r chi.Router) {
+		r.Post("/{provider}/login", h.Auth.SocialLogin)
+
+		r.Group(func(r chi.Router) {
+			r.Use(h.Auth.RequirePlayer)
+			r.Get("/accounts", h.Auth.ListSocialAccounts)
+			r.Post("/{provider}", h.Auth.LinkSocialAccount)
+			r.Delete("/{provider}", h.Auth.UnlinkSocialAccount)
+		})
 	})
 
 	return r
@@ -0,0 +1,113 @@
+package playerhttp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"players_service/internal/domain/auth"
+	authuc "players_service/internal/usecase/auth"
+)
+
+func (h *AuthHTTP) ListSocialAccounts(w http.ResponseWriter, r *http.Request) {
+	accounts, err := h.uc.ListSocialAccounts(r.Context(), claimsFrom(r.Context()).PlayerID)
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	items := make([]map[string]any, 0, len(accounts))
+	for _, a := range accounts {
+		items = append(items, toSocialDTO(a))
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+type idTokenReq struct {
+	IDToken string `json:"id_token"`
+}
+
+func (h *AuthHTTP) LinkSocialAccount(w http.ResponseWriter, r *http.Request) {
+	var req idTokenReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_json")
+		return
+	}
+
+	a, err := h.uc.LinkSocialAccount(r.Context(), claimsFrom(r.Context()).PlayerID, chi.URLParam(r, "provider"), req.IDToken)
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toSocialDTO(a))
+}
+
+func (h *AuthHTTP) UnlinkSocialAccount(w http.ResponseWriter, r *http.Request) {
+	err := h.uc.UnlinkSocialAccount(r.Context(), claimsFrom(r.Context()).PlayerID, chi.URLParam(r, "provider"))
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, true)
+}
+
+type socialLoginReq struct {
+	IDToken   string `json:"id_token"`
+	BirthDate string `json:"birth_date"` // YYYY-MM-DD, optional
+	Country   string `json:"country"`
+	Currency  string `json:"currency"`
+	Locale    string `json:"locale"`
+	TimeZone  string `json:"time_zone"`
+}
+
+// SocialLogin logs in, or registers, through a provider ID token.
+func (h *AuthHTTP) SocialLogin(w http.ResponseWriter, r *http.Request) {
+	var req socialLoginReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_json")
+		return
+	}
+
+	birth, err := parseDate(req.BirthDate)
+	if err != nil {
+		writeErr(w, http.StatusBadRequest, "bad_birth_date")
+		return
+	}
+
+	res, err := h.uc.SocialLogin(r.Context(), authuc.SocialLoginCmd{
+		Provider:    chi.URLParam(r, "provider"),
+		IDToken:     req.IDToken,
+		IP:          clientIP(r),
+		UserAgent:   r.UserAgent(),
+		BirthDate:   birth,
+		CountryCode: req.Country,
+		Currency:    req.Currency,
+		Locale:      req.Locale,
+		TimeZone:    req.TimeZone,
+	})
+	if err != nil {
+		encodeDomainErr(w, err)
+		return
+	}
+
+	code := http.StatusOK
+	if res.Created {
+		code = http.StatusCreated
+	}
+	writeJSON(w, code, map[string]any{
+		"player": toPlayerDTO(res.Player),
+		"tokens": toTokensDTO(res.Tokens),
+	})
+}
+
+func toSocialDTO(a auth.SocialAccount) map[string]any {
+	return map[string]any{
+		"provider":  string(a.Provider),
+		"subject":   a.Subject,
+		"email":     a.Email,
+		"linked_at": fmtTime(a.LinkedAt),
+	}
+}
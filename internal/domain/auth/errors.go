@@ -0,0 +1,21 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"players_service/internal/domain/player"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrWeakPassword       = errors.New("weak password")
+	ErrUnauthenticated    = errors.New("unauthenticated")
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrWrongPassword      = errors.New("wrong password")
+	ErrInvalidResetToken  = errors.New("invalid reset token")
+	ErrLoginThrottled     = errors.New("too many failed logins")
+
+	// ErrPlayerInactive is returned for blocked, frozen and closed players.
+	ErrPlayerInactive = fmt.Errorf("%w: player is not active", player.ErrForbidden)
+)
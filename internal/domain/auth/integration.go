@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/player"
+)
+
+// IntegrationClaims is what an integration token asserts to a game provider:
+// which player it is, in which currency it plays, and for which provider the
+// token was minted.
+type IntegrationClaims struct {
+	PlayerID  uuid.UUID
+	Currency  string
+	Provider  string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+var reIntegrationProvider = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+
+// NormalizeIntegrationProvider validates a game provider name.
+func NormalizeIntegrationProvider(s string) (string, error) {
+	v := strings.ToLower(strings.TrimSpace(s))
+	if !reIntegrationProvider.MatchString(v) {
+		return "", fmt.Errorf("%w: bad provider %q", player.ErrValidation, s)
+	}
+	return v, nil
+}
@@ -0,0 +1,19 @@
+package auth
+
+import (
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LockoutReason is the status reason of players frozen for failed logins.
+const LockoutReason = "too many failed login attempts"
+
+// LoginFailure is one failed password check. PlayerID is uuid.Nil when the
+// login did not match any player.
+type LoginFailure struct {
+	PlayerID uuid.UUID
+	IP       net.IP
+	At       time.Time
+}
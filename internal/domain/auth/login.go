@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LoginMethod is how the player authenticated: MethodPassword or the name
+// of a social provider.
+type LoginMethod string
+
+const MethodPassword LoginMethod = "password"
+
+func SocialMethod(p Provider) LoginMethod { return LoginMethod(p) }
+
+type LoginOutcome string
+
+const (
+	LoginSucceeded   LoginOutcome = "success"
+	LoginBadPassword LoginOutcome = "invalid_password"
+	LoginInactive    LoginOutcome = "inactive"
+	LoginThrottled   LoginOutcome = "throttled"
+)
+
+// LoginRecord is one entry of a player's login history.
+type LoginRecord struct {
+	ID        uuid.UUID
+	PlayerID  uuid.UUID
+	IP        net.IP
+	UserAgent string
+	Method    LoginMethod
+	Outcome   LoginOutcome
+	CreatedAt time.Time
+}
+
+func NewLoginRecord(playerID uuid.UUID, method LoginMethod, outcome LoginOutcome, ip net.IP, userAgent string, now time.Time) LoginRecord {
+	return LoginRecord{
+		ID:        uuid.New(),
+		PlayerID:  playerID,
+		IP:        ip,
+		UserAgent: userAgent,
+		Method:    method,
+		Outcome:   outcome,
+		CreatedAt: now,
+	}
+}
+
+// SharedIP is another player who logged in successfully from an IP the
+// player logged in from. Logins counts those successful logins.
+type SharedIP struct {
+	PlayerID    uuid.UUID
+	IP          net.IP
+	Logins      int
+	LastLoginAt time.Time
+}
+
+// LoginFilter selects a page of login history.
+type LoginFilter struct {
+	PlayerID uuid.UUID
+	After    *LoginCursor
+	Limit    int
+}
+
+// LoginCursor points at the last record of the previous page.
+type LoginCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 128
+)
+
+// PasswordCredential is a player's password, only the hash is ever stored.
+type PasswordCredential struct {
+	PlayerID  uuid.UUID
+	Hash      string // PHC string, see infra/password
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func ValidatePassword(pw string) error {
+	n := utf8.RuneCountInString(pw)
+	if n < MinPasswordLength {
+		return fmt.Errorf("%w: at least %d characters required", ErrWeakPassword, MinPasswordLength)
+	}
+	if n > MaxPasswordLength {
+		return fmt.Errorf("%w: at most %d characters allowed", ErrWeakPassword, MaxPasswordLength)
+	}
+	return nil
+}
+
+func NewPasswordCredential(playerID uuid.UUID, hash string, now time.Time) PasswordCredential {
+	return PasswordCredential{
+		PlayerID:  playerID,
+		Hash:      hash,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordReset is a single-use permission to set a new password, issued once
+// the player proved access to a contact. Only the token hash is stored.
+type PasswordReset struct {
+	ID        uuid.UUID
+	PlayerID  uuid.UUID
+	TokenHash string
+	IP        net.IP
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    time.Time
+}
+
+// NewPasswordReset returns the reset with its plain token for the client.
+func NewPasswordReset(playerID uuid.UUID, ip net.IP, ttl time.Duration, now time.Time) (PasswordReset, string, error) {
+	tok, err := newRefreshToken()
+	if err != nil {
+		return PasswordReset{}, "", err
+	}
+	return PasswordReset{
+		ID:        uuid.New(),
+		PlayerID:  playerID,
+		TokenHash: HashRefreshToken(tok),
+		IP:        ip,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}, tok, nil
+}
+
+func (r PasswordReset) Usable(now time.Time) bool {
+	return r.UsedAt.IsZero() && now.Before(r.ExpiresAt)
+}
+
+// Password audit actions.
+const (
+	AuditResetRequested = "reset_requested"
+	AuditResetCompleted = "reset_completed"
+	AuditChanged        = "changed"
+)
+
+// PasswordAudit records a password related action and where it came from.
+type PasswordAudit struct {
+	ID        uuid.UUID
+	PlayerID  uuid.UUID
+	Action    string
+	IP        net.IP
+	CreatedAt time.Time
+}
+
+func NewPasswordAudit(playerID uuid.UUID, action string, ip net.IP, now time.Time) PasswordAudit {
+	return PasswordAudit{
+		ID:        uuid.New(),
+		PlayerID:  playerID,
+		Action:    action,
+		IP:        ip,
+		CreatedAt: now,
+	}
+}
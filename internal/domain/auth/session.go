@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reasons a session ends.
+const (
+	RevokeLogout  = "logout"
+	RevokeRotated = "rotated"
+	RevokeReuse   = "refresh_reuse"
+	RevokeKick    = "kick"
+	RevokeStatus  = "status_changed"
+	RevokeReset   = "password_reset"
+	RevokeChange  = "password_changed"
+)
+
+// Session is a server-side login. It holds the hash of the current refresh
+// token; every refresh rotates the token into a new session of the same family.
+type Session struct {
+	ID           uuid.UUID
+	PlayerID     uuid.UUID
+	FamilyID     uuid.UUID // all sessions produced by rotating one login
+	RefreshHash  string
+	IP           net.IP
+	UserAgent    string
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+	RevokedAt    time.Time
+	RevokeReason string
+}
+
+// NewSession starts a session and returns it with the plain refresh token,
+// which is handed to the client and never stored.
+func NewSession(playerID, familyID uuid.UUID, ip net.IP, userAgent string, ttl time.Duration, now time.Time) (Session, string, error) {
+	refresh, err := newRefreshToken()
+	if err != nil {
+		return Session{}, "", err
+	}
+	if familyID == uuid.Nil {
+		familyID = uuid.New()
+	}
+	return Session{
+		ID:          uuid.New(),
+		PlayerID:    playerID,
+		FamilyID:    familyID,
+		RefreshHash: HashRefreshToken(refresh),
+		IP:          ip,
+		UserAgent:   userAgent,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+	}, refresh, nil
+}
+
+func (s Session) Active(now time.Time) bool {
+	return s.RevokedAt.IsZero() && now.Before(s.ExpiresAt)
+}
+
+// HashRefreshToken is what gets stored and looked up; refresh tokens are
+// random 256-bit values, so a plain sha256 is enough.
+func HashRefreshToken(tok string) string {
+	sum := sha256.Sum256([]byte(tok))
+	return hex.EncodeToString(sum[:])
+}
+
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// AccessClaims is what a signed access token asserts.
+type AccessClaims struct {
+	PlayerID  uuid.UUID
+	SessionID uuid.UUID
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// Tokens is the result of a login or refresh.
+type Tokens struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/player"
+)
+
+// Provider is an external identity provider.
+type Provider string
+
+const (
+	ProviderGoogle   Provider = "google"
+	ProviderTelegram Provider = "telegram"
+)
+
+func ParseProvider(s string) (Provider, error) {
+	switch p := Provider(strings.ToLower(strings.TrimSpace(s))); p {
+	case ProviderGoogle, ProviderTelegram:
+		return p, nil
+	default:
+		return "", fmt.Errorf("%w: unknown provider %s", player.ErrValidation, s)
+	}
+}
+
+// ExternalIdentity is what a provider asserts about the token holder.
+type ExternalIdentity struct {
+	Provider      Provider
+	Subject       string // stable id at the provider
+	Email         string // optional
+	EmailVerified bool
+}
+
+// SocialAccount links an external identity to a player. (Provider, Subject)
+// is unique, and a player has at most one account per provider.
+type SocialAccount struct {
+	Provider Provider
+	Subject  string
+	PlayerID uuid.UUID
+	Email    string
+	LinkedAt time.Time
+}
+
+func NewSocialAccount(playerID uuid.UUID, id ExternalIdentity, now time.Time) (SocialAccount, error) {
+	if strings.TrimSpace(id.Subject) == "" {
+		return SocialAccount{}, fmt.Errorf("%w: empty subject", ErrInvalidToken)
+	}
+	return SocialAccount{
+		Provider: id.Provider,
+		Subject:  id.Subject,
+		PlayerID: playerID,
+		Email:    strings.ToLower(strings.TrimSpace(id.Email)),
+		LinkedAt: now,
+	}, nil
+}
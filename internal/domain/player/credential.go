@@ -0,0 +1,117 @@
+package player
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CredentialKind int16
+
+const (
+	CredentialUnknown CredentialKind = 0
+	CredentialEmail   CredentialKind = 1
+	CredentialPhone   CredentialKind = 2
+)
+
+func (k CredentialKind) String() string {
+	switch k {
+	case CredentialEmail:
+		return "email"
+	case CredentialPhone:
+		return "phone"
+	default:
+		return "unknown"
+	}
+}
+
+// Credential is an email or phone the player can be reached and log in by.
+// Values are unique across all players; the primary one of each kind is the
+// Email / Phone of the player.
+type Credential struct {
+	ID         uuid.UUID
+	PlayerID   uuid.UUID
+	Kind       CredentialKind
+	Value      string
+	Primary    bool
+	VerifiedAt time.Time
+	CreatedAt  time.Time
+}
+
+// NormalizeCredential validates value for kind and returns its stored form.
+func NormalizeCredential(kind CredentialKind, value string) (string, error) {
+	switch kind {
+	case CredentialEmail:
+		v := strings.ToLower(strings.TrimSpace(value))
+		if !reEmail.MatchString(v) {
+			return "", fmt.Errorf("%w: %s", ErrInvalidEmail, value)
+		}
+		return v, nil
+	case CredentialPhone:
+		return NormalizePhone(value)
+	default:
+		return "", fmt.Errorf("%w: unknown credential kind", ErrValidation)
+	}
+}
+
+// NormalizePhone returns phone in E.164 form, "+" and up to 15 digits, so one
+// number written in different ways is stored and looked up as one value.
+// Spaces, dots, dashes and parentheses are dropped, a leading "00" is read as
+// "+", and digits without either are taken to start with the country code.
+func NormalizePhone(phone string) (string, error) {
+	s := strings.TrimSpace(phone)
+	switch {
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	case strings.HasPrefix(s, "00"):
+		s = s[2:]
+	}
+	var b strings.Builder
+	b.WriteByte('+')
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ' || r == '.' || r == '-' || r == '(' || r == ')':
+		default:
+			return "", fmt.Errorf("%w: %s", ErrInvalidPhone, phone)
+		}
+	}
+	v := b.String()
+	if !rePhone.MatchString(v) {
+		return "", fmt.Errorf("%w: %s", ErrInvalidPhone, phone)
+	}
+	return v, nil
+}
+
+// AddCredential returns a new confirmed credential of the player. It becomes
+// primary when the player has no value of that kind yet (only possible for
+// the phone), and then the player takes it over as its Phone.
+func (p *Player) AddCredential(kind CredentialKind, value string, verifiedAt, now time.Time) (Credential, error) {
+	v, err := NormalizeCredential(kind, value)
+	if err != nil {
+		return Credential{}, err
+	}
+	if (kind == CredentialEmail && v == p.Email) || (kind == CredentialPhone && v == p.Phone) {
+		return Credential{}, fmt.Errorf("%w: %s already added", ErrConflict, kind)
+	}
+
+	c := Credential{
+		ID:         uuid.New(),
+		PlayerID:   p.ID,
+		Kind:       kind,
+		Value:      v,
+		VerifiedAt: verifiedAt,
+		CreatedAt:  now,
+	}
+	if kind == CredentialPhone && p.Phone == "" {
+		c.Primary = true
+		p.Phone = v
+		p.PhoneVerifiedAt = verifiedAt
+		p.Version++
+		p.UpdatedAt = now
+	}
+	return c, nil
+}
@@ -0,0 +1,52 @@
+package player
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"+447911123456", "+447911123456"},
+		{" +44 7911 123456 ", "+447911123456"},
+		{"0044 (7911) 123-456", "+447911123456"},
+		{"447911123456", "+447911123456"},
+		{"+1.202.555.0143", "+12025550143"},
+	}
+	for _, tt := range tests {
+		got, err := NormalizePhone(tt.in)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Fatalf("%q = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "+", "12345", "+0447911123456", "+4479111234567890", "+44 7911 12345x", "+44+7911123456"} {
+		if _, err := NormalizePhone(in); !errors.Is(err, ErrInvalidPhone) {
+			t.Fatalf("%q: err = %v, want ErrInvalidPhone", in, err)
+		}
+	}
+}
+
+func TestUpdateProfileSamePhoneOtherSpelling(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	p := &Player{Email: "a@example.com", Phone: "+447911123456", PhoneVerifiedAt: now, Status: StatusActive, Version: 1}
+
+	phone := "0044 7911 123456"
+	changed, err := p.UpdateProfile(ProfileUpdate{Phone: &phone}, JurisdictionPolicy{}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 0 || p.Version != 1 || p.PhoneVerifiedAt.IsZero() {
+		t.Fatalf("changed = %v, version = %d, verified = %v", changed, p.Version, p.PhoneVerifiedAt)
+	}
+	if err := p.MarkPhoneVerified("+44 7911 123456", now); !errors.Is(err, ErrValidation) {
+		t.Fatalf("MarkPhoneVerified: err = %v, want already verified", err)
+	}
+}
@@ -0,0 +1,174 @@
+package player
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DocumentType int16
+
+const (
+	DocumentUnknown        DocumentType = 0
+	DocumentPassport       DocumentType = 1
+	DocumentIDCard         DocumentType = 2
+	DocumentDriverLicense  DocumentType = 3
+	DocumentProofOfAddress DocumentType = 4
+	DocumentSelfie         DocumentType = 5
+	DocumentPaymentMethod  DocumentType = 6
+)
+
+func (t DocumentType) String() string {
+	switch t {
+	case DocumentPassport:
+		return "passport"
+	case DocumentIDCard:
+		return "id_card"
+	case DocumentDriverLicense:
+		return "driver_license"
+	case DocumentProofOfAddress:
+		return "proof_of_address"
+	case DocumentSelfie:
+		return "selfie"
+	case DocumentPaymentMethod:
+		return "payment_method"
+	default:
+		return "unknown"
+	}
+}
+
+func ParseDocumentType(v string) (DocumentType, error) {
+	switch v {
+	case "passport":
+		return DocumentPassport, nil
+	case "id_card":
+		return DocumentIDCard, nil
+	case "driver_license":
+		return DocumentDriverLicense, nil
+	case "proof_of_address":
+		return DocumentProofOfAddress, nil
+	case "selfie":
+		return DocumentSelfie, nil
+	case "payment_method":
+		return DocumentPaymentMethod, nil
+	default:
+		return DocumentUnknown, fmt.Errorf("%w: %s", ErrInvalidDocumentType, v)
+	}
+}
+
+type DocumentStatus int16
+
+const (
+	DocumentStatusUnknown  DocumentStatus = 0
+	DocumentStatusPending  DocumentStatus = 1
+	DocumentStatusApproved DocumentStatus = 2
+	DocumentStatusRejected DocumentStatus = 3
+)
+
+func (s DocumentStatus) String() string {
+	switch s {
+	case DocumentStatusPending:
+		return "pending"
+	case DocumentStatusApproved:
+		return "approved"
+	case DocumentStatusRejected:
+		return "rejected"
+	default:
+		return "unknown"
+	}
+}
+
+func ParseDocumentStatus(v string) (DocumentStatus, error) {
+	switch v {
+	case "pending":
+		return DocumentStatusPending, nil
+	case "approved":
+		return DocumentStatusApproved, nil
+	case "rejected":
+		return DocumentStatusRejected, nil
+	default:
+		return DocumentStatusUnknown, fmt.Errorf("%w: %s", ErrInvalidDocumentStatus, v)
+	}
+}
+
+// Document is a KYC document uploaded by the player. FileRef points into
+// the document storage and is opaque to the domain.
+type Document struct {
+	ID        uuid.UUID
+	PlayerID  uuid.UUID
+	Type      DocumentType
+	FileRef   string
+	Status    DocumentStatus
+	Reason    string // why the document was rejected, empty otherwise
+	Version   int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewDocument(playerID uuid.UUID, typ DocumentType, fileRef string, now time.Time) (*Document, error) {
+	if typ == DocumentUnknown {
+		return nil, ErrInvalidDocumentType
+	}
+	if strings.TrimSpace(fileRef) == "" {
+		return nil, fmt.Errorf("%w: file required", ErrValidation)
+	}
+	return &Document{
+		ID:        uuid.New(),
+		PlayerID:  playerID,
+		Type:      typ,
+		FileRef:   fileRef,
+		Status:    DocumentStatusPending,
+		Version:   1,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// Review records a decision on the document. A decision can be revised
+// later, e.g. an approved document can be rejected once it turns out forged.
+func (d *Document) Review(to DocumentStatus, reason string, actor ActorType, now time.Time) (DocumentReview, error) {
+	if to != DocumentStatusApproved && to != DocumentStatusRejected {
+		return DocumentReview{}, fmt.Errorf("%w: review ends in approved or rejected", ErrInvalidDocumentStatus)
+	}
+	if to == d.Status {
+		return DocumentReview{}, fmt.Errorf("%w: document already %s", ErrValidation, to.String())
+	}
+	reason = strings.TrimSpace(reason)
+	if to == DocumentStatusRejected && reason == "" {
+		return DocumentReview{}, fmt.Errorf("%w: rejection reason required", ErrValidation)
+	}
+	if to == DocumentStatusApproved {
+		reason = ""
+	}
+
+	from := d.Status
+	d.Status = to
+	d.Reason = reason
+	d.Version++
+	d.UpdatedAt = now
+
+	return DocumentReview{
+		ID:         uuid.New(),
+		DocumentID: d.ID,
+		PlayerID:   d.PlayerID,
+		From:       from,
+		To:         to,
+		Reason:     reason,
+		ActorType:  actor,
+		CreatedAt:  now,
+	}, nil
+}
+
+// DocumentReview is the audit record of a review decision.
+type DocumentReview struct {
+	ID         uuid.UUID
+	DocumentID uuid.UUID
+	PlayerID   uuid.UUID
+	From       DocumentStatus
+	To         DocumentStatus
+	Reason     string
+	ActorType  ActorType
+	CreatedAt  time.Time
+}
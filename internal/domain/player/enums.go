@@ -111,3 +111,16 @@ func (a ActorType) String() string {
 		return "system"
 	}
 }
+
+func ParseActorType(v string) (ActorType, error) {
+	switch v {
+	case "player":
+		return ActorPlayer, nil
+	case "administrator":
+		return ActorAdmin, nil
+	case "system":
+		return ActorSystem, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrInvalidActorType, v)
+	}
+}
@@ -10,6 +10,17 @@ var (
 	ErrInvalidCountryCode = errors.New("invalid country_code")
 	ErrInvalidTimeZone    = errors.New("invalid time_zone")
 	ErrInvalidLocale      = errors.New("invalid locale")
+	ErrInvalidActorType   = errors.New("invalid actor_type")
+
+	ErrInvalidDocumentType   = errors.New("invalid document type")
+	ErrInvalidDocumentStatus = errors.New("invalid document status")
+	ErrInvalidKYCLevel       = errors.New("invalid kyc_level")
+	ErrInvalidExclusionKind  = errors.New("invalid self-exclusion kind")
+
+	ErrUnderage          = errors.New("player is under the minimum age")
+	ErrBirthDateRequired = errors.New("birth_date required")
+
+	ErrRestrictedJurisdiction = errors.New("restricted jurisdiction")
 
 	ErrNotFound   = errors.New("player not found")
 	ErrConflict   = errors.New("conflict")
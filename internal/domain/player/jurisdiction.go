@@ -0,0 +1,144 @@
+package player
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JurisdictionPolicy holds the rules of the countries players live in.
+type JurisdictionPolicy struct {
+	DefaultMinAge     int
+	MinAge            map[string]int  // by country code, overrides DefaultMinAge
+	BirthDateRequired map[string]bool // countries where players must give a birth date
+	Restricted        map[string]bool // countries players may not come from
+	// RejectUnknownIPCountry refuses registrations from an IP that could not
+	// be geolocated, so that restricted countries cannot hide behind ranges
+	// missing from the database.
+	RejectUnknownIPCountry bool
+}
+
+// DefaultJurisdictionPolicy: 18 everywhere, birth date optional, no
+// restricted countries.
+func DefaultJurisdictionPolicy() JurisdictionPolicy {
+	return JurisdictionPolicy{
+		DefaultMinAge:     18,
+		MinAge:            map[string]int{},
+		BirthDateRequired: map[string]bool{},
+		Restricted:        map[string]bool{},
+	}
+}
+
+func (j JurisdictionPolicy) MinAgeFor(countryCode string) int {
+	if age, ok := j.MinAge[countryCode]; ok {
+		return age
+	}
+	return j.DefaultMinAge
+}
+
+// ParseMinAges reads a comma separated list of "country:age" entries,
+// e.g. "US:21,EE:21".
+func ParseMinAges(spec string) (map[string]int, error) {
+	out := map[string]int{}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		country, ageStr, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: bad min age %q", ErrValidation, item)
+		}
+		country = strings.ToUpper(strings.TrimSpace(country))
+		if !reCountry.MatchString(country) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCountryCode, country)
+		}
+		age, err := strconv.Atoi(strings.TrimSpace(ageStr))
+		if err != nil || age < 0 {
+			return nil, fmt.Errorf("%w: bad min age %q", ErrValidation, item)
+		}
+		out[country] = age
+	}
+	return out, nil
+}
+
+// ParseCountries reads a comma separated list of country codes.
+func ParseCountries(spec string) (map[string]bool, error) {
+	out := map[string]bool{}
+	for _, c := range strings.Split(spec, ",") {
+		c = strings.ToUpper(strings.TrimSpace(c))
+		if c == "" {
+			continue
+		}
+		if !reCountry.MatchString(c) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCountryCode, c)
+		}
+		out[c] = true
+	}
+	return out, nil
+}
+
+// AgeAt returns the age in full years at now, as of the calendar date in
+// time zone tz (UTC when empty). birth is a date, only its Y-M-D is used.
+// Someone born on Feb 29 comes of age on Mar 1 in common years.
+func AgeAt(birth time.Time, tz string, now time.Time) (int, error) {
+	loc := time.UTC
+	if tz != "" {
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s", ErrInvalidTimeZone, tz)
+		}
+		loc = l
+	}
+	y, m, d := now.In(loc).Date()
+	by, bm, bd := birth.Date()
+
+	age := y - by
+	if m < bm || (m == bm && d < bd) {
+		age--
+	}
+	return age, nil
+}
+
+// checkAge enforces the age rules of j for the player's country.
+func (p *Player) checkAge(j JurisdictionPolicy, now time.Time) error {
+	required := j.BirthDateRequired[p.Address.CountryCode]
+	minAge := j.MinAgeFor(p.Address.CountryCode)
+
+	if p.BirthDate.IsZero() {
+		if required {
+			return fmt.Errorf("%w: country %s", ErrBirthDateRequired, p.Address.CountryCode)
+		}
+		return nil
+	}
+
+	age, err := AgeAt(p.BirthDate, p.Address.TimeZone, now)
+	if err != nil {
+		return err
+	}
+	if age < 0 {
+		return fmt.Errorf("%w: birth_date is in the future", ErrValidation)
+	}
+	if age < minAge {
+		return fmt.Errorf("%w: minimum age in %s is %d", ErrUnderage, p.Address.CountryCode, minAge)
+	}
+	return nil
+}
+
+// checkRestricted rejects players from restricted countries. ip is where
+// the request came from, nil when not known, ipCountry its country, empty when
+// it could not be geolocated.
+func (p *Player) checkRestricted(j JurisdictionPolicy, ip net.IP, ipCountry string) error {
+	if j.Restricted[p.Address.CountryCode] {
+		return fmt.Errorf("%w: country %s", ErrRestrictedJurisdiction, p.Address.CountryCode)
+	}
+	if j.Restricted[ipCountry] {
+		return fmt.Errorf("%w: ip country %s", ErrRestrictedJurisdiction, ipCountry)
+	}
+	if len(ip) > 0 && ipCountry == "" && j.RejectUnknownIPCountry {
+		return fmt.Errorf("%w: ip country unknown", ErrRestrictedJurisdiction)
+	}
+	return nil
+}
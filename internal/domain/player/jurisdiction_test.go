@@ -0,0 +1,131 @@
+package player
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestAgeAt(t *testing.T) {
+	birth := date(2008, time.June, 15)
+
+	tests := []struct {
+		name  string
+		birth time.Time
+		tz    string
+		now   time.Time
+		want  int
+	}{
+		{"day before 18th birthday", birth, "", time.Date(2026, time.June, 14, 23, 59, 0, 0, time.UTC), 17},
+		{"18th birthday", birth, "", time.Date(2026, time.June, 15, 0, 0, 0, 0, time.UTC), 18},
+		{"day after 18th birthday", birth, "", time.Date(2026, time.June, 16, 12, 0, 0, 0, time.UTC), 18},
+		{"feb 29 birth, feb 28 of common year", date(2008, time.February, 29), "", date(2026, time.February, 28), 17},
+		{"feb 29 birth, mar 1 of common year", date(2008, time.February, 29), "", date(2026, time.March, 1), 18},
+		{"feb 29 birth, feb 29 of leap year", date(2008, time.February, 29), "", date(2028, time.February, 29), 20},
+		// 22:00 UTC on Jun 14 is already Jun 15 in Tokyo
+		{"ahead of utc, birthday already local", birth, "Asia/Tokyo", time.Date(2026, time.June, 14, 22, 0, 0, 0, time.UTC), 18},
+		// 02:00 UTC on Jun 15 is still Jun 14 in New York
+		{"behind utc, birthday not yet local", birth, "America/New_York", time.Date(2026, time.June, 15, 2, 0, 0, 0, time.UTC), 17},
+		{"birth date in the future", date(2030, time.January, 1), "", date(2026, time.January, 1), -4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AgeAt(tt.birth, tt.tz, tt.now)
+			if err != nil {
+				t.Fatalf("AgeAt: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("AgeAt = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAgeAtBadTimeZone(t *testing.T) {
+	_, err := AgeAt(date(2000, time.January, 1), "Mars/Olympus_Mons", date(2026, time.January, 1))
+	if !errors.Is(err, ErrInvalidTimeZone) {
+		t.Fatalf("want ErrInvalidTimeZone, got %v", err)
+	}
+}
+
+func TestCheckAge(t *testing.T) {
+	now := date(2026, time.June, 15)
+	j := DefaultJurisdictionPolicy()
+	j.MinAge["US"] = 21
+	j.BirthDateRequired["GB"] = true
+
+	tests := []struct {
+		name    string
+		country string
+		birth   time.Time
+		wantErr error
+	}{
+		{"18 by default", "DE", date(2008, time.June, 15), nil},
+		{"17 by default", "DE", date(2008, time.June, 16), ErrUnderage},
+		{"18 where 21 is required", "US", date(2008, time.June, 15), ErrUnderage},
+		{"21 where 21 is required", "US", date(2005, time.June, 15), nil},
+		{"no birth date where optional", "DE", time.Time{}, nil},
+		{"no birth date where required", "GB", time.Time{}, ErrBirthDateRequired},
+		{"future birth date", "DE", date(2027, time.January, 1), ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Player{BirthDate: tt.birth, Address: Address{CountryCode: tt.country}}
+			err := p.checkAge(j, now)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("checkAge: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestNewPlayerRestricted(t *testing.T) {
+	now := date(2026, time.June, 15)
+	j := DefaultJurisdictionPolicy()
+	j.Restricted["US"] = true
+	j.RejectUnknownIPCountry = true
+	ip := net.ParseIP("203.0.113.7")
+
+	tests := []struct {
+		name      string
+		country   string
+		ip        net.IP
+		ipCountry string
+		wantErr   bool
+	}{
+		{"allowed", "DE", ip, "DE", false},
+		{"declared restricted country", "US", ip, "DE", true},
+		{"ip in restricted country", "DE", ip, "US", true},
+		{"ip not geolocated", "DE", ip, "", true},
+		{"no ip", "DE", nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPlayer(CreateParams{
+				Email:                 "p@example.com",
+				Address:               Address{CountryCode: tt.country},
+				RegistrationIP:        tt.ip,
+				RegistrationIPCountry: tt.ipCountry,
+			}, j, now)
+			if tt.wantErr != errors.Is(err, ErrRestrictedJurisdiction) {
+				t.Fatalf("got %v", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("NewPlayer: %v", err)
+			}
+		})
+	}
+
+	j.RejectUnknownIPCountry = false
+	if _, err := NewPlayer(CreateParams{Email: "p@example.com", RegistrationIP: ip}, j, now); err != nil {
+		t.Fatalf("unknown ip country allowed by the policy: %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package player
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type KYCLevel int16
+
+const (
+	KYCNone  KYCLevel = 0
+	KYCBasic KYCLevel = 1
+	KYCFull  KYCLevel = 2
+)
+
+func (l KYCLevel) String() string {
+	switch l {
+	case KYCBasic:
+		return "basic"
+	case KYCFull:
+		return "full"
+	default:
+		return "none"
+	}
+}
+
+func ParseKYCLevel(v string) (KYCLevel, error) {
+	switch v {
+	case "none":
+		return KYCNone, nil
+	case "basic":
+		return KYCBasic, nil
+	case "full":
+		return KYCFull, nil
+	default:
+		return KYCNone, fmt.Errorf("%w: %s", ErrInvalidKYCLevel, v)
+	}
+}
+
+// KYCRequirement is met when any of its document types is approved.
+type KYCRequirement []DocumentType
+
+// KYCRule lists the requirements of each level. Full also needs everything
+// basic does.
+type KYCRule struct {
+	Basic []KYCRequirement
+	Full  []KYCRequirement
+}
+
+// Level returns the highest level reached with the approved document types.
+func (r KYCRule) Level(approved []DocumentType) KYCLevel {
+	have := make(map[DocumentType]bool, len(approved))
+	for _, t := range approved {
+		have[t] = true
+	}
+	met := func(reqs []KYCRequirement) bool {
+		for _, req := range reqs {
+			ok := false
+			for _, t := range req {
+				ok = ok || have[t]
+			}
+			if !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch {
+	case !met(r.Basic):
+		return KYCNone
+	case !met(r.Full):
+		return KYCBasic
+	default:
+		return KYCFull
+	}
+}
+
+// KYCRules maps a country code to its rule, Default covers the rest.
+type KYCRules struct {
+	Default   KYCRule
+	Countries map[string]KYCRule
+}
+
+func (r KYCRules) For(countryCode string) KYCRule {
+	if rule, ok := r.Countries[countryCode]; ok {
+		return rule
+	}
+	return r.Default
+}
+
+// DefaultKYCRules: an identity document gives basic, proof of address and
+// a selfie on top of it give full. The same for every country.
+func DefaultKYCRules() KYCRules {
+	return KYCRules{
+		Default: KYCRule{
+			Basic: []KYCRequirement{{DocumentPassport, DocumentIDCard, DocumentDriverLicense}},
+			Full:  []KYCRequirement{{DocumentProofOfAddress}, {DocumentSelfie}},
+		},
+		Countries: map[string]KYCRule{},
+	}
+}
+
+// ParseKYCRules reads a comma separated list of "country:level=types;level=types"
+// entries, where types are "+" separated requirements of "|" separated
+// alternatives, and "*" is the country of the default rule, e.g.
+// "*:basic=passport|id_card;full=proof_of_address+selfie,DE:basic=id_card;full=proof_of_address".
+// The default rule stays built in unless "*" is given.
+func ParseKYCRules(spec string) (KYCRules, error) {
+	r := DefaultKYCRules()
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		country, levels, ok := strings.Cut(item, ":")
+		if !ok {
+			return KYCRules{}, fmt.Errorf("%w: bad kyc rule %q", ErrValidation, item)
+		}
+		country = strings.ToUpper(strings.TrimSpace(country))
+		if country != "*" && !reCountry.MatchString(country) {
+			return KYCRules{}, fmt.Errorf("%w: %s", ErrInvalidCountryCode, country)
+		}
+
+		var rule KYCRule
+		for _, lv := range strings.Split(levels, ";") {
+			name, types, ok := strings.Cut(lv, "=")
+			if !ok {
+				return KYCRules{}, fmt.Errorf("%w: bad kyc rule %q", ErrValidation, item)
+			}
+			reqs, err := parseKYCRequirements(types)
+			if err != nil {
+				return KYCRules{}, err
+			}
+			level, err := ParseKYCLevel(strings.TrimSpace(name))
+			if err != nil {
+				return KYCRules{}, err
+			}
+			switch level {
+			case KYCBasic:
+				rule.Basic = reqs
+			case KYCFull:
+				rule.Full = reqs
+			default:
+				return KYCRules{}, fmt.Errorf("%w: level none has no requirements", ErrValidation)
+			}
+		}
+		if len(rule.Basic) == 0 || len(rule.Full) == 0 {
+			return KYCRules{}, fmt.Errorf("%w: kyc rule %q needs basic and full", ErrValidation, item)
+		}
+
+		if country == "*" {
+			r.Default = rule
+		} else {
+			r.Countries[country] = rule
+		}
+	}
+	return r, nil
+}
+
+func parseKYCRequirements(spec string) ([]KYCRequirement, error) {
+	var reqs []KYCRequirement
+	for _, part := range strings.Split(spec, "+") {
+		var req KYCRequirement
+		for _, name := range strings.Split(part, "|") {
+			t, err := ParseDocumentType(strings.TrimSpace(name))
+			if err != nil {
+				return nil, err
+			}
+			req = append(req, t)
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
+
+// RecomputeKYCLevel sets the level reached with the approved document types
+// under the rule of the player's country. It returns the previous level and
+// whether it changed.
+func (p *Player) RecomputeKYCLevel(rules KYCRules, approved []DocumentType, now time.Time) (KYCLevel, bool) {
+	level := rules.For(p.Address.CountryCode).Level(approved)
+
+	from := p.KYCLevel
+	if level == from {
+		return from, false
+	}
+	p.KYCLevel = level
+	p.Version++
+	p.UpdatedAt = now
+	return from, true
+}
@@ -0,0 +1,44 @@
+package player
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecomputeKYCLevel(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	rules, err := ParseKYCRules("DE:basic=id_card;full=proof_of_address")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		country  string
+		approved []DocumentType
+		want     KYCLevel
+	}{
+		{"nothing approved", "GB", nil, KYCNone},
+		{"passport gives basic", "GB", []DocumentType{DocumentPassport}, KYCBasic},
+		{"full needs selfie too", "GB", []DocumentType{DocumentPassport, DocumentProofOfAddress}, KYCBasic},
+		{"full", "GB", []DocumentType{DocumentDriverLicense, DocumentProofOfAddress, DocumentSelfie}, KYCFull},
+		{"address without identity", "GB", []DocumentType{DocumentProofOfAddress, DocumentSelfie}, KYCNone},
+		{"country rule: passport not enough", "DE", []DocumentType{DocumentPassport}, KYCNone},
+		{"country rule: full", "DE", []DocumentType{DocumentIDCard, DocumentProofOfAddress}, KYCFull},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Player{Address: Address{CountryCode: tt.country}, Version: 1}
+			from, changed := p.RecomputeKYCLevel(rules, tt.approved, now)
+			if from != KYCNone {
+				t.Fatalf("from = %s", from)
+			}
+			if p.KYCLevel != tt.want {
+				t.Fatalf("level = %s, want %s", p.KYCLevel, tt.want)
+			}
+			if changed != (tt.want != KYCNone) {
+				t.Fatalf("changed = %v", changed)
+			}
+		})
+	}
+}
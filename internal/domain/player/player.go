@@ -11,20 +11,27 @@ import (
 )
 
 type Player struct {
-	ID             uuid.UUID
-	Email          string
-	Phone          string
-	Status         Status
-	StatusReason   string
-	Address        Address
-	FirstName      string
-	LastName       string
-	BirthDate      time.Time
-	Gender         Gender
-	RegistrationIP net.IP
-	RegisteredAt   time.Time
-	LastLoginAt    time.Time
-	Metadata       map[string]any
+	ID                    uuid.UUID
+	Email                 string
+	EmailVerifiedAt       time.Time // zero until confirmed
+	Phone                 string
+	PhoneVerifiedAt       time.Time // zero until confirmed, reset when the phone changes
+	Status                Status
+	StatusReason          string
+	StatusUntil           time.Time // zero for permanent statuses
+	KYCLevel              KYCLevel  // derived from approved documents
+	ExclusionKind         ExclusionKind
+	ExcludedUntil         time.Time // end of a self-exclusion, zero for permanent
+	Address               Address
+	FirstName             string
+	LastName              string
+	BirthDate             time.Time
+	Gender                Gender
+	RegistrationIP        net.IP
+	RegistrationIPCountry string // geolocated from RegistrationIP, empty when unknown
+	RegisteredAt          time.Time
+	LastLoginAt           time.Time
+	Metadata              map[string]any
 
 	Version   int64
 	CreatedAt time.Time
@@ -33,49 +40,57 @@ type Player struct {
 
 var (
 	reEmail = regexp.MustCompile(`^[^\s@]+@[^\s@]+\.[^\s@]+$`)
-	rePhone = regexp.MustCompile(`^\+?[0-9]{6,20}$`)
+	rePhone = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`) // E.164
 )
 
 type CreateParams struct {
-	Email          string
-	Phone          string
-	FirstName      string
-	LastName       string
-	BirthDate      time.Time
-	Gender         Gender
-	Address        Address
-	RegistrationIP net.IP
-	Metadata       map[string]any
-	RegisteredAt   time.Time
+	Email                 string
+	Phone                 string
+	FirstName             string
+	LastName              string
+	BirthDate             time.Time
+	Gender                Gender
+	Address               Address
+	RegistrationIP        net.IP
+	RegistrationIPCountry string
+	Metadata              map[string]any
+	RegisteredAt          time.Time
 }
 
-func NewPlayer(p CreateParams, now time.Time) (*Player, error) {
+// NewPlayer validates p and checks it against the jurisdiction policy j.
+func NewPlayer(p CreateParams, j JurisdictionPolicy, now time.Time) (*Player, error) {
 	email := strings.TrimSpace(strings.ToLower(p.Email))
 	if email == "" || !reEmail.MatchString(email) {
 		return nil, fmt.Errorf("%w: %s", ErrInvalidEmail, p.Email)
 	}
-	if p.Phone != "" && !rePhone.MatchString(p.Phone) {
-		return nil, fmt.Errorf("%w: %s", ErrInvalidPhone, p.Phone)
+	phone := strings.TrimSpace(p.Phone)
+	if phone != "" {
+		v, err := NormalizePhone(phone)
+		if err != nil {
+			return nil, err
+		}
+		phone = v
 	}
 	if err := p.Address.Validate(); err != nil {
 		return nil, err
 	}
 
 	pl := &Player{
-		ID:             uuid.New(),
-		Email:          email,
-		Phone:          p.Phone,
-		Status:         StatusActive,
-		StatusReason:   "",
-		Address:        p.Address,
-		FirstName:      p.FirstName,
-		LastName:       p.LastName,
-		BirthDate:      p.BirthDate,
-		Gender:         p.Gender,
-		RegistrationIP: p.RegistrationIP,
-		RegisteredAt:   p.RegisteredAt,
-		LastLoginAt:    time.Time{},
-		Metadata:       p.Metadata,
+		ID:                    uuid.New(),
+		Email:                 email,
+		Phone:                 phone,
+		Status:                StatusActive,
+		StatusReason:          "",
+		Address:               p.Address,
+		FirstName:             p.FirstName,
+		LastName:              p.LastName,
+		BirthDate:             p.BirthDate,
+		Gender:                p.Gender,
+		RegistrationIP:        p.RegistrationIP,
+		RegistrationIPCountry: p.RegistrationIPCountry,
+		RegisteredAt:          p.RegisteredAt,
+		LastLoginAt:           time.Time{},
+		Metadata:              p.Metadata,
 
 		Version:   1,
 		CreatedAt: now,
@@ -85,6 +100,12 @@ func NewPlayer(p CreateParams, now time.Time) (*Player, error) {
 	if err := pl.Validate(); err != nil {
 		return nil, err
 	}
+	if err := pl.checkRestricted(j, pl.RegistrationIP, pl.RegistrationIPCountry); err != nil {
+		return nil, err
+	}
+	if err := pl.checkAge(j, now); err != nil {
+		return nil, err
+	}
 	return pl, nil
 }
 
@@ -104,28 +125,94 @@ func (p *Player) Validate() error {
 	return nil
 }
 
-// Domain rule: status change must include non-empty reason.
-func (p *Player) ChangeStatus(to Status, reason string, actor ActorType, now time.Time) (PlayerStatusEvent, error) {
+// Domain rule: status change must include non-empty reason and be allowed by rules.
+func (p *Player) ChangeStatus(rules TransitionRules, to Status, reason string, actor ActorType, now time.Time) (PlayerStatusEvent, error) {
+	return p.ChangeStatusUntil(rules, to, reason, actor, time.Time{}, now)
+}
+
+// ChangeStatusUntil is ChangeStatus with an expiry: a non-zero until makes a
+// blocked or frozen status temporary, it is reverted to active once expired.
+func (p *Player) ChangeStatusUntil(rules TransitionRules, to Status, reason string, actor ActorType, until time.Time, now time.Time) (PlayerStatusEvent, error) {
 	if to == StatusUnknown {
 		return PlayerStatusEvent{}, ErrInvalidStatus
 	}
-	if to == p.Status {
+	if !until.IsZero() {
+		if to != StatusBlocked && to != StatusFrozen {
+			return PlayerStatusEvent{}, fmt.Errorf("%w: only blocked or frozen status can be temporary", ErrValidation)
+		}
+		if !until.After(now) {
+			return PlayerStatusEvent{}, fmt.Errorf("%w: status_until must be in the future", ErrValidation)
+		}
+	}
+	if to == p.Status && until.Equal(p.StatusUntil) {
 		return PlayerStatusEvent{}, fmt.Errorf("%w: status already %s", ErrValidation, to.String())
 	}
 	if strings.TrimSpace(reason) == "" {
 		return PlayerStatusEvent{}, fmt.Errorf("%w: status_reason required", ErrValidation)
 	}
+	if err := p.checkExclusion(to, now); err != nil {
+		return PlayerStatusEvent{}, err
+	}
+	if err := p.checkTransition(rules, to, actor, now); err != nil {
+		return PlayerStatusEvent{}, err
+	}
 
 	from := p.Status
 	p.Status = to
 	p.StatusReason = reason
+	p.StatusUntil = until
+	if !p.Excluded(now) {
+		// an exclusion that ran out is cleared by the next change
+		p.ExclusionKind = ExclusionNone
+		p.ExcludedUntil = time.Time{}
+	}
 	p.Version++
 	p.UpdatedAt = now
 
 	ev := NewPlayerStatusEvent(p.ID, from, to, reason, actor, now)
+	ev.Until = until
 	return ev, nil
 }
 
+// StatusExpired reports whether a temporary status has run out at now.
+func (p *Player) StatusExpired(now time.Time) bool {
+	return !p.StatusUntil.IsZero() && !now.Before(p.StatusUntil)
+}
+
+// CanLogin reports whether the player may start a new session.
+func (p *Player) CanLogin() bool {
+	return p.Status == StatusActive
+}
+
+// MarkEmailVerified confirms email, which must still be the player's email.
+func (p *Player) MarkEmailVerified(email string, now time.Time) error {
+	if !strings.EqualFold(strings.TrimSpace(email), p.Email) {
+		return fmt.Errorf("%w: email changed", ErrConflict)
+	}
+	if !p.EmailVerifiedAt.IsZero() {
+		return fmt.Errorf("%w: email already verified", ErrValidation)
+	}
+	p.EmailVerifiedAt = now
+	p.Version++
+	p.UpdatedAt = now
+	return nil
+}
+
+// MarkPhoneVerified confirms phone, which must still be the player's phone.
+func (p *Player) MarkPhoneVerified(phone string, now time.Time) error {
+	v, err := NormalizePhone(phone)
+	if p.Phone == "" || err != nil || v != p.Phone {
+		return fmt.Errorf("%w: phone changed", ErrConflict)
+	}
+	if !p.PhoneVerifiedAt.IsZero() {
+		return fmt.Errorf("%w: phone already verified", ErrValidation)
+	}
+	p.PhoneVerifiedAt = now
+	p.Version++
+	p.UpdatedAt = now
+	return nil
+}
+
 func (p *Player) MarkLogin(at time.Time) {
 	p.LastLoginAt = at
 	p.Version++
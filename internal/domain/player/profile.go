@@ -0,0 +1,84 @@
+package player
+
+import (
+	"strings"
+	"time"
+)
+
+// ProfileUpdate is a partial profile change, nil fields are left untouched.
+type ProfileUpdate struct {
+	FirstName *string
+	LastName  *string
+	Phone     *string
+	BirthDate *time.Time
+	Gender    *Gender
+	Address   *Address
+}
+
+// UpdateProfile applies u and returns names of the fields that actually changed.
+// Nothing is modified (and version is not bumped) when the result is invalid,
+// breaks the jurisdiction policy j or is empty.
+func (p *Player) UpdateProfile(u ProfileUpdate, j JurisdictionPolicy, now time.Time) ([]string, error) {
+	next := *p
+	var changed []string
+
+	if u.FirstName != nil && strings.TrimSpace(*u.FirstName) != next.FirstName {
+		next.FirstName = strings.TrimSpace(*u.FirstName)
+		changed = append(changed, "first_name")
+	}
+	if u.LastName != nil && strings.TrimSpace(*u.LastName) != next.LastName {
+		next.LastName = strings.TrimSpace(*u.LastName)
+		changed = append(changed, "last_name")
+	}
+	if u.Phone != nil {
+		phone := strings.TrimSpace(*u.Phone)
+		if phone != "" {
+			v, err := NormalizePhone(phone)
+			if err != nil {
+				return nil, err
+			}
+			phone = v
+		}
+		if phone != next.Phone {
+			next.Phone = phone
+			next.PhoneVerifiedAt = time.Time{}
+			changed = append(changed, "phone")
+		}
+	}
+	if u.BirthDate != nil && !u.BirthDate.Equal(next.BirthDate) {
+		next.BirthDate = *u.BirthDate
+		changed = append(changed, "birth_date")
+	}
+	if u.Gender != nil && *u.Gender != next.Gender {
+		next.Gender = *u.Gender
+		changed = append(changed, "gender")
+	}
+	if u.Address != nil && *u.Address != next.Address {
+		next.Address = *u.Address
+		changed = append(changed, "address")
+	}
+
+	if len(changed) == 0 {
+		return nil, nil
+	}
+	if err := next.Validate(); err != nil {
+		return nil, err
+	}
+	// the age rules only apply to what changes, existing players are not
+	// locked out of unrelated edits by a stricter policy
+	if next.Address.CountryCode != p.Address.CountryCode {
+		if err := next.checkRestricted(j, nil, ""); err != nil {
+			return nil, err
+		}
+	}
+	if !next.BirthDate.Equal(p.BirthDate) || next.Address != p.Address {
+		if err := next.checkAge(j, now); err != nil {
+			return nil, err
+		}
+	}
+
+	next.Version++
+	next.UpdatedAt = now
+	*p = next
+	return changed, nil
+}
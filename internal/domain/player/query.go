@@ -0,0 +1,49 @@
+package player
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Query types shared by the usecases and the repositories that serve them.
+
+// Whitelisted sort fields for player listing.
+const (
+	SortCreatedAt    = "created_at"
+	SortRegisteredAt = "registered_at"
+	SortLastLoginAt  = "last_login_at"
+	SortEmail        = "email"
+	SortStatus       = "status"
+	SortCountry      = "country_code"
+)
+
+// ListFilter is an already validated list query.
+// SortBy is one of the Sort* constants, repositories may rely on that.
+type ListFilter struct {
+	Offset   int
+	Limit    int
+	Search   string
+	Country  string
+	Currency string
+	SortBy   string
+	Desc     bool
+
+	EmailVerified *bool // nil means any
+	PhoneVerified *bool
+	KYCLevel      *KYCLevel
+}
+
+type StatusEventFilter struct {
+	PlayerID  uuid.UUID
+	ActorType ActorType // 0 means any
+	ToStatus  Status    // StatusUnknown means any
+	After     *StatusEventCursor
+	Limit     int
+}
+
+// StatusEventCursor points at the last event of the previous page.
+type StatusEventCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
@@ -13,6 +13,7 @@ type PlayerStatusEvent struct {
 	To        Status
 	Reason    string
 	ActorType ActorType
+	Until     time.Time // zero for permanent statuses
 	CreatedAt time.Time
 }
 
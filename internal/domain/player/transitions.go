@@ -0,0 +1,98 @@
+package player
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Transition is a single allowed status move for an actor type.
+type Transition struct {
+	From  Status
+	To    Status
+	Actor ActorType
+}
+
+// TransitionRules is the status transition matrix, anything not listed is forbidden.
+type TransitionRules map[Transition]struct{}
+
+func (r TransitionRules) Allows(from, to Status, actor ActorType) bool {
+	_, ok := r[Transition{From: from, To: to, Actor: actor}]
+	return ok
+}
+
+// DefaultTransitionRules:
+//   - players may only close their own account;
+//   - administrators may move between any statuses, including reopening closed
+//     accounts and re-applying blocked/frozen to change the expiry;
+//   - the system may only freeze and unfreeze.
+func DefaultTransitionRules() TransitionRules {
+	r := TransitionRules{}
+	add := func(actor ActorType, from, to Status) {
+		r[Transition{From: from, To: to, Actor: actor}] = struct{}{}
+	}
+
+	add(ActorPlayer, StatusActive, StatusClosed)
+
+	for _, from := range []Status{StatusActive, StatusBlocked, StatusFrozen, StatusClosed} {
+		for _, to := range []Status{StatusActive, StatusBlocked, StatusFrozen, StatusClosed} {
+			if from == to && (to == StatusActive || to == StatusClosed) {
+				continue
+			}
+			add(ActorAdmin, from, to)
+		}
+	}
+
+	add(ActorSystem, StatusActive, StatusFrozen)
+	add(ActorSystem, StatusFrozen, StatusActive)
+	return r
+}
+
+// ParseTransitionRules reads a comma separated list of "actor:from>to" entries,
+// e.g. "player:active>closed,administrator:closed>active".
+func ParseTransitionRules(spec string) (TransitionRules, error) {
+	r := TransitionRules{}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		actorStr, move, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: bad transition %q", ErrValidation, item)
+		}
+		fromStr, toStr, ok := strings.Cut(move, ">")
+		if !ok {
+			return nil, fmt.Errorf("%w: bad transition %q", ErrValidation, item)
+		}
+		actor, err := ParseActorType(strings.TrimSpace(actorStr))
+		if err != nil {
+			return nil, err
+		}
+		from, err := ParseStatus(strings.TrimSpace(fromStr))
+		if err != nil {
+			return nil, err
+		}
+		to, err := ParseStatus(strings.TrimSpace(toStr))
+		if err != nil {
+			return nil, err
+		}
+		r[Transition{From: from, To: to, Actor: actor}] = struct{}{}
+	}
+	if len(r) == 0 {
+		return nil, fmt.Errorf("%w: empty transition rules", ErrValidation)
+	}
+	return r, nil
+}
+
+// checkTransition enforces the transition matrix. Reverting an expired temporary
+// status to active is always allowed for the system, whatever the matrix says.
+func (p *Player) checkTransition(rules TransitionRules, to Status, actor ActorType, now time.Time) error {
+	if actor == ActorSystem && to == StatusActive && p.StatusExpired(now) {
+		return nil
+	}
+	if !rules.Allows(p.Status, to, actor) {
+		return fmt.Errorf("%w: %s may not change status %s -> %s", ErrForbidden, actor.String(), p.Status.String(), to.String())
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package player
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestChangeStatusTransitionMatrix(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	rules := DefaultTransitionRules()
+
+	tests := []struct {
+		name    string
+		from    Status
+		to      Status
+		actor   ActorType
+		allowed bool
+	}{
+		{"player closes own account", StatusActive, StatusClosed, ActorPlayer, true},
+		{"player cannot block", StatusActive, StatusBlocked, ActorPlayer, false},
+		{"player cannot reopen", StatusClosed, StatusActive, ActorPlayer, false},
+		{"player cannot unfreeze", StatusFrozen, StatusActive, ActorPlayer, false},
+		{"admin blocks", StatusActive, StatusBlocked, ActorAdmin, true},
+		{"admin reopens closed", StatusClosed, StatusActive, ActorAdmin, true},
+		{"system freezes", StatusActive, StatusFrozen, ActorSystem, true},
+		{"system unfreezes", StatusFrozen, StatusActive, ActorSystem, true},
+		{"system cannot close", StatusActive, StatusClosed, ActorSystem, false},
+		{"system cannot unblock", StatusBlocked, StatusActive, ActorSystem, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Player{Status: tt.from, Version: 1}
+			_, err := p.ChangeStatus(rules, tt.to, "reason", tt.actor, now)
+			if tt.allowed {
+				if err != nil {
+					t.Fatalf("ChangeStatus: %v", err)
+				}
+				if p.Status != tt.to || p.Version != 2 {
+					t.Fatalf("got status %s version %d", p.Status.String(), p.Version)
+				}
+				return
+			}
+			if !errors.Is(err, ErrForbidden) {
+				t.Fatalf("want ErrForbidden, got %v", err)
+			}
+			if p.Status != tt.from || p.Version != 1 {
+				t.Fatalf("player changed on a forbidden transition")
+			}
+		})
+	}
+}
+
+func TestChangeStatusCustomRules(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	rules, err := ParseTransitionRules("administrator:active>blocked")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Player{Status: StatusActive}
+	if _, err := p.ChangeStatus(rules, StatusClosed, "reason", ActorPlayer, now); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("closing not in the matrix: want ErrForbidden, got %v", err)
+	}
+	if _, err := p.ChangeStatus(rules, StatusBlocked, "reason", ActorAdmin, now); err != nil {
+		t.Fatalf("blocking: %v", err)
+	}
+}
+
+func TestChangeStatusExpiredRevertBypassesMatrix(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	p := &Player{Status: StatusBlocked, StatusUntil: now.Add(-time.Minute)}
+
+	// the default matrix does not let the system unblock
+	if _, err := p.ChangeStatus(DefaultTransitionRules(), StatusActive, "expired", ActorSystem, now); err != nil {
+		t.Fatalf("reverting an expired block: %v", err)
+	}
+	if p.Status != StatusActive || !p.StatusUntil.IsZero() {
+		t.Fatalf("got status %s until %v", p.Status.String(), p.StatusUntil)
+	}
+}
@@ -0,0 +1,116 @@
+package verification
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/player"
+)
+
+// We store enums as ints (SMALLINT) in DB, like the player domain does.
+
+type Channel int16
+
+const (
+	ChannelUnknown Channel = 0
+	ChannelEmail   Channel = 1
+	ChannelPhone   Channel = 2
+)
+
+func (c Channel) String() string {
+	switch c {
+	case ChannelEmail:
+		return "email"
+	case ChannelPhone:
+		return "phone"
+	default:
+		return "unknown"
+	}
+}
+
+// Purpose scopes a code: a registration code cannot be used to reset a password.
+type Purpose string
+
+const (
+	PurposeRegistration Purpose = "registration"
+	PurposeVerify       Purpose = "verify"
+	PurposeReset        Purpose = "password_reset"
+	PurposeCredential   Purpose = "add_credential"
+)
+
+// Destination is where a code is delivered.
+type Destination struct {
+	Channel Channel
+	Value   string
+}
+
+var (
+	reEmail = regexp.MustCompile(`^[^\s@]+@[^\s@]+\.[^\s@]+$`)
+)
+
+func EmailDestination(email string) (Destination, error) {
+	v := strings.ToLower(strings.TrimSpace(email))
+	if !reEmail.MatchString(v) {
+		return Destination{}, fmt.Errorf("%w: %s", ErrInvalidDestination, email)
+	}
+	return Destination{Channel: ChannelEmail, Value: v}, nil
+}
+
+func PhoneDestination(phone string) (Destination, error) {
+	v, err := player.NormalizePhone(phone)
+	if err != nil {
+		return Destination{}, fmt.Errorf("%w: %s", ErrInvalidDestination, phone)
+	}
+	return Destination{Channel: ChannelPhone, Value: v}, nil
+}
+
+// Code is an issued one-time code. Only a keyed hash of the value is kept.
+type Code struct {
+	ID          uuid.UUID
+	Purpose     Purpose
+	Destination Destination
+	Hash        string
+	Attempts    int
+	MaxAttempts int
+	IP          net.IP
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+	ConsumedAt  time.Time
+}
+
+// Usable tells whether c may still take a guess at now. It is only a
+// pre-check: the guess itself is counted atomically by the repository.
+func (c Code) Usable(now time.Time) error {
+	if !c.ConsumedAt.IsZero() {
+		return ErrInvalidCode
+	}
+	if !now.Before(c.ExpiresAt) {
+		return ErrCodeExpired
+	}
+	if c.Attempts >= c.MaxAttempts {
+		return ErrTooManyAttempts
+	}
+	return nil
+}
+
+// GenerateDigits returns a uniformly random numeric code of the given length.
+func GenerateDigits(length int) (string, error) {
+	var b strings.Builder
+	b.Grow(length)
+	ten := big.NewInt(10)
+	for i := 0; i < length; i++ {
+		n, err := rand.Int(rand.Reader, ten)
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(byte('0' + n.Int64()))
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,11 @@
+package verification
+
+import "errors"
+
+var (
+	ErrInvalidCode        = errors.New("invalid code")
+	ErrCodeExpired        = errors.New("code expired")
+	ErrTooManyAttempts    = errors.New("too many attempts")
+	ErrTooManyRequests    = errors.New("too many requests")
+	ErrInvalidDestination = errors.New("invalid destination")
+)
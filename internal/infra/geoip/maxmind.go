@@ -0,0 +1,53 @@
+package geoip
+
+import (
+	"net"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// MaxMind looks countries up in a local MaxMind-format database file
+// (GeoLite2/GeoIP2 Country or City, or any mmdb with the same layout).
+// It never goes to the network.
+//
+// The database is not shipped with the service, its license does not allow
+// it. In production GEOIP_DB_PATH points at GeoLite2-Country.mmdb (or the
+// paid GeoIP2-Country.mmdb) downloaded from MaxMind with an account license
+// key, and kept fresh with MaxMind's geoipupdate: the data changes twice a
+// week. Tests use testdata/country-test.mmdb, see testdata/gen.go.
+type MaxMind struct {
+	db *maxminddb.Reader
+}
+
+func Open(path string) (*MaxMind, error) {
+	db, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &MaxMind{db: db}, nil
+}
+
+type countryRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	RegisteredCountry struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"registered_country"`
+}
+
+// Country returns the ISO code of the country of ip, empty when the
+// database does not know it.
+func (m *MaxMind) Country(ip net.IP) (string, error) {
+	var rec countryRecord
+	if err := m.db.Lookup(ip, &rec); err != nil {
+		return "", err
+	}
+	if rec.Country.ISOCode != "" {
+		return rec.Country.ISOCode, nil
+	}
+	// anycast and satellite ranges only have the registration country
+	return rec.RegisteredCountry.ISOCode, nil
+}
+
+func (m *MaxMind) Close() error { return m.db.Close() }
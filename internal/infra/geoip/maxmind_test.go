@@ -0,0 +1,51 @@
+package geoip
+
+import (
+	"net"
+	"testing"
+)
+
+// testdata/country-test.mmdb is generated by testdata/gen.go.
+func TestMaxMindCountry(t *testing.T) {
+	m, err := Open("testdata/country-test.mmdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = m.Close() })
+	if err := m.db.Verify(); err != nil {
+		t.Fatalf("fixture is not a valid mmdb: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		ip   string
+		want string
+	}{
+		{"known ipv4", "81.2.69.160", "GB"},
+		{"other network", "89.160.20.112", "SE"},
+		{"ipv4 mapped ipv6", "::ffff:81.2.69.160", "GB"},
+		{"known ipv6", "2001:218::1", "JP"},
+		{"registered country only", "214.78.120.1", "US"},
+		{"unknown", "8.8.8.8", ""},
+		{"unknown ipv6", "2a00:1450::1", ""},
+		{"private", "10.1.2.3", ""},
+		{"loopback", "127.0.0.1", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.Country(net.ParseIP(tt.ip))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("Country(%s) = %q, want %q", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenMissingFile(t *testing.T) {
+	if _, err := Open("testdata/missing.mmdb"); err == nil {
+		t.Fatal("want an error")
+	}
+}
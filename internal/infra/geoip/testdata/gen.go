@@ -0,0 +1,170 @@
+//go:build ignore
+
+// gen writes country-test.mmdb, a tiny MaxMind-format country database for
+// the tests of the geoip package. Run it from this directory:
+//
+//	go run gen.go
+//
+// The layout follows the MaxMind DB spec (https://maxmind.github.io/MaxMind-DB/):
+// an IPv6 search tree with 24-bit records, IPv4 networks under ::/96.
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log"
+	"net"
+	"os"
+	"time"
+)
+
+var networks = []struct {
+	cidr       string
+	country    string // empty: the network only has a registered country
+	registered string
+}{
+	{"81.2.69.0/24", "GB", "GB"},
+	{"89.160.20.0/24", "SE", "SE"},
+	{"214.78.120.0/22", "", "US"}, // e.g. an anycast range
+	{"2001:218::/32", "JP", "JP"},
+}
+
+type node struct {
+	child [2]*node
+	data  int // offset in the data section + 1, 0 when not a leaf
+}
+
+func main() {
+	root := &node{}
+	var data bytes.Buffer
+	for _, n := range networks {
+		_, ipnet, err := net.ParseCIDR(n.cidr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		offset := data.Len()
+		rec := [][2]any{}
+		if n.country != "" {
+			rec = append(rec, [2]any{"country", [][2]any{{"iso_code", n.country}}})
+		}
+		rec = append(rec, [2]any{"registered_country", [][2]any{{"iso_code", n.registered}}})
+		encode(&data, rec)
+
+		ip, ones := ipnet.IP.To16(), 0
+		if v4 := ipnet.IP.To4(); v4 != nil {
+			ip = append(make(net.IP, 12), v4...) // ::a.b.c.d
+			o, _ := ipnet.Mask.Size()
+			ones = 96 + o
+		} else {
+			ones, _ = ipnet.Mask.Size()
+		}
+		insert(root, ip, ones, offset+1)
+	}
+
+	// number the inner nodes breadth first, root is 0
+	var order []*node
+	index := map[*node]int{}
+	for queue := []*node{root}; len(queue) > 0; queue = queue[1:] {
+		n := queue[0]
+		index[n] = len(order)
+		order = append(order, n)
+		for _, c := range n.child {
+			if c != nil && c.data == 0 {
+				queue = append(queue, c)
+			}
+		}
+	}
+	count := len(order)
+
+	var out bytes.Buffer
+	for _, n := range order {
+		for _, c := range n.child {
+			v := count // empty
+			switch {
+			case c == nil:
+			case c.data != 0:
+				v = count + 16 + c.data - 1
+			default:
+				v = index[c]
+			}
+			out.Write([]byte{byte(v >> 16), byte(v >> 8), byte(v)})
+		}
+	}
+	out.Write(make([]byte, 16))
+	out.Write(data.Bytes())
+
+	out.WriteString("\xab\xcd\xefMaxMind.com")
+	encode(&out, [][2]any{
+		{"binary_format_major_version", uint16(2)},
+		{"binary_format_minor_version", uint16(0)},
+		{"build_epoch", uint64(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Unix())},
+		{"database_type", "GeoIP2-Country-Test"},
+		{"description", [][2]any{{"en", "geoip package test data"}}},
+		{"ip_version", uint16(6)},
+		{"languages", []string{"en"}},
+		{"node_count", uint32(count)},
+		{"record_size", uint16(24)},
+	})
+
+	if err := os.WriteFile("country-test.mmdb", out.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func insert(n *node, ip net.IP, ones, data int) {
+	for i := 0; i < ones; i++ {
+		bit := ip[i/8] >> (7 - uint(i%8)) & 1
+		if n.child[bit] == nil {
+			n.child[bit] = &node{}
+		}
+		n = n.child[bit]
+	}
+	n.data = data
+}
+
+func control(buf *bytes.Buffer, typ, size int) {
+	if size >= 29 {
+		log.Fatalf("size %d not supported", size)
+	}
+	if typ <= 7 {
+		buf.WriteByte(byte(typ<<5 | size))
+		return
+	}
+	buf.WriteByte(byte(size))
+	buf.WriteByte(byte(typ - 7))
+}
+
+func encode(buf *bytes.Buffer, v any) {
+	switch v := v.(type) {
+	case string:
+		control(buf, 2, len(v))
+		buf.WriteString(v)
+	case uint16:
+		encodeUint(buf, 5, uint64(v))
+	case uint32:
+		encodeUint(buf, 6, uint64(v))
+	case uint64:
+		encodeUint(buf, 9, v)
+	case []string:
+		control(buf, 11, len(v))
+		for _, s := range v {
+			encode(buf, s)
+		}
+	case [][2]any: // map, keys in order
+		control(buf, 7, len(v))
+		for _, kv := range v {
+			encode(buf, kv[0])
+			encode(buf, kv[1])
+		}
+	default:
+		log.Fatalf("cannot encode %T", v)
+	}
+}
+
+func encodeUint(buf *bytes.Buffer, typ int, v uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	raw := bytes.TrimLeft(b[:], "\x00")
+	control(buf, typ, len(raw))
+	buf.Write(raw)
+}
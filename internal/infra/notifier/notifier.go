@@ -0,0 +1,57 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	verificationuc "players_service/internal/usecase/verification"
+)
+
+// Log writes codes to the service log. For local development only: codes are secrets.
+type Log struct{}
+
+func NewLog() Log { return Log{} }
+
+func (Log) Notify(_ context.Context, m verificationuc.Message) error {
+	log.Printf("verification code %s for %s %s: %s (expires %s)",
+		m.Purpose, m.Destination.Channel, m.Destination.Value, m.Code, m.ExpiresAt.Format(time.RFC3339))
+	return nil
+}
+
+// File appends codes as JSON lines, so local tooling and tests can pick them up.
+type File struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+func NewFile(path string) (*File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &File{f: f}, nil
+}
+
+func (n *File) Notify(_ context.Context, m verificationuc.Message) error {
+	line, err := json.Marshal(map[string]any{
+		"purpose":     string(m.Purpose),
+		"channel":     m.Destination.Channel.String(),
+		"destination": m.Destination.Value,
+		"code":        m.Code,
+		"expires_at":  m.ExpiresAt.Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, err = n.f.Write(append(line, '\n'))
+	return err
+}
+
+func (n *File) Close() error { return n.f.Close() }
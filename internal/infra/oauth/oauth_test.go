@@ -0,0 +1,248 @@
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"players_service/internal/domain/auth"
+	"players_service/internal/infra/token"
+)
+
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func TestStubRoundTrip(t *testing.T) {
+	clk := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	keys := token.NewStaticKeys(token.Key{ID: "k1", Secret: []byte("0123456789abcdef0123456789abcdef")})
+	google := NewStub(auth.ProviderGoogle, keys, clk)
+
+	id := auth.ExternalIdentity{Provider: auth.ProviderGoogle, Subject: "g-1", Email: "p@example.com", EmailVerified: true}
+	tok, err := google.Issue(id, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := google.Verify(context.Background(), tok)
+	if err != nil || got != id {
+		t.Fatalf("got %+v, %v", got, err)
+	}
+
+	// same keys, other provider: the issuer does not match
+	if _, err := NewStub(auth.ProviderTelegram, keys, clk).Verify(context.Background(), tok); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Fatalf("other provider: %v", err)
+	}
+	clk.now = clk.now.Add(time.Minute)
+	if _, err := google.Verify(context.Background(), tok); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Fatalf("expired: %v", err)
+	}
+}
+
+type testIdP struct {
+	key     *rsa.PrivateKey
+	kid     string
+	fetches int
+	srv     *httptest.Server
+}
+
+func newTestIdP(t *testing.T) *testIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &testIdP{key: key, kid: "k1"}
+	idp.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		idp.fetches++
+		w.Header().Set("Cache-Control", "public, max-age=600")
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": idp.kid,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(idp.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(idp.key.E)).Bytes()),
+		}}})
+	}))
+	t.Cleanup(idp.srv.Close)
+	return idp
+}
+
+func (idp *testIdP) sign(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	seg := func(v any) string {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	signed := seg(map[string]string{"alg": "RS256", "kid": idp.kid, "typ": "JWT"}) + "." + seg(claims)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, idp.key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestOIDCVerify(t *testing.T) {
+	idp := newTestIdP(t)
+	clk := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	cfg := OIDCConfig{
+		Issuers:   []string{"https://accounts.google.com"},
+		JWKSURL:   idp.srv.URL,
+		Audiences: []string{"our-client"},
+		Leeway:    time.Minute,
+	}
+	v, err := NewOIDC(auth.ProviderGoogle, cfg, idp.srv.Client(), clk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := func(mod func(map[string]any)) map[string]any {
+		c := map[string]any{
+			"iss":            "https://accounts.google.com",
+			"sub":            "g-1",
+			"aud":            "our-client",
+			"iat":            clk.now.Unix(),
+			"exp":            clk.now.Add(time.Hour).Unix(),
+			"email":          "p@example.com",
+			"email_verified": true,
+		}
+		if mod != nil {
+			mod(c)
+		}
+		return c
+	}
+	ctx := context.Background()
+
+	got, err := v.Verify(ctx, idp.sign(t, claims(nil)))
+	want := auth.ExternalIdentity{Provider: auth.ProviderGoogle, Subject: "g-1", Email: "p@example.com", EmailVerified: true}
+	if err != nil || got != want {
+		t.Fatalf("got %+v, %v", got, err)
+	}
+	got, err = v.Verify(ctx, idp.sign(t, claims(func(c map[string]any) {
+		c["aud"] = []string{"someone-else", "our-client"}
+		c["email_verified"] = "false"
+	})))
+	if err != nil || got.EmailVerified {
+		t.Fatalf("aud list: got %+v, %v", got, err)
+	}
+
+	for name, mod := range map[string]func(map[string]any){
+		"other audience": func(c map[string]any) { c["aud"] = "someone-else" },
+		"other issuer":   func(c map[string]any) { c["iss"] = "https://evil.example.com" },
+		"expired":        func(c map[string]any) { c["exp"] = clk.now.Add(-2 * time.Minute).Unix() },
+		"future iat":     func(c map[string]any) { c["iat"] = clk.now.Add(time.Hour).Unix() },
+		"no subject":     func(c map[string]any) { delete(c, "sub") },
+	} {
+		if _, err := v.Verify(ctx, idp.sign(t, claims(mod))); !errors.Is(err, auth.ErrInvalidToken) {
+			t.Errorf("%s: want ErrInvalidToken, got %v", name, err)
+		}
+	}
+
+	tok := idp.sign(t, claims(nil))
+	forged := tok[:len(tok)-4] + "AAAA"
+	if _, err := v.Verify(ctx, forged); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("bad signature: %v", err)
+	}
+	if idp.fetches != 1 {
+		t.Fatalf("%d key fetches, want the cached set reused", idp.fetches)
+	}
+}
+
+func TestOIDCKeyRotation(t *testing.T) {
+	idp := newTestIdP(t)
+	clk := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	cfg := OIDCConfig{Issuers: []string{"iss"}, JWKSURL: idp.srv.URL, Audiences: []string{"aud"}}
+	v, err := NewOIDC(auth.ProviderGoogle, cfg, idp.srv.Client(), clk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims := map[string]any{"iss": "iss", "sub": "s", "aud": "aud", "iat": clk.now.Unix(), "exp": clk.now.Add(time.Hour).Unix()}
+	ctx := context.Background()
+	if _, err := v.Verify(ctx, idp.sign(t, claims)); err != nil {
+		t.Fatal(err)
+	}
+
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp.key, idp.kid = newKey, "k2"
+
+	// an unknown kid right after a fetch does not hit the provider again
+	if _, err := v.Verify(ctx, idp.sign(t, claims)); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Fatalf("unknown kid: %v", err)
+	}
+	if idp.fetches != 1 {
+		t.Fatalf("%d fetches, want 1", idp.fetches)
+	}
+
+	clk.now = clk.now.Add(jwksMinRefresh)
+	if _, err := v.Verify(ctx, idp.sign(t, claims)); err != nil {
+		t.Fatalf("rotated key: %v", err)
+	}
+	if idp.fetches != 2 {
+		t.Fatalf("%d fetches, want 2", idp.fetches)
+	}
+}
+
+func telegramData(botToken string, vals url.Values) string {
+	v := &Telegram{}
+	key := sha256.Sum256([]byte(botToken))
+	v.secret = key[:]
+	vals.Set("hash", hex.EncodeToString(v.sign(vals)))
+	return vals.Encode()
+}
+
+func TestTelegramVerify(t *testing.T) {
+	clk := &fakeClock{now: time.Unix(1_700_000_000, 0)}
+	v, err := NewTelegram("123:bot-token", time.Hour, clk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := func() url.Values {
+		return url.Values{
+			"id":         {"42"},
+			"first_name": {"Ann"},
+			"username":   {"ann"},
+			"auth_date":  {strconv.FormatInt(clk.now.Add(-time.Minute).Unix(), 10)},
+		}
+	}
+	ctx := context.Background()
+
+	got, err := v.Verify(ctx, telegramData("123:bot-token", fields()))
+	if err != nil || got != (auth.ExternalIdentity{Provider: auth.ProviderTelegram, Subject: "42"}) {
+		t.Fatalf("got %+v, %v", got, err)
+	}
+
+	tampered, _ := url.ParseQuery(telegramData("123:bot-token", fields()))
+	tampered.Set("id", "43")
+	if _, err := v.Verify(ctx, tampered.Encode()); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("tampered: %v", err)
+	}
+	if _, err := v.Verify(ctx, telegramData("456:other-bot", fields())); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("other bot: %v", err)
+	}
+	old := fields()
+	old.Set("auth_date", strconv.FormatInt(clk.now.Add(-2*time.Hour).Unix(), 10))
+	if _, err := v.Verify(ctx, telegramData("123:bot-token", old)); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("stale: %v", err)
+	}
+	if _, err := v.Verify(ctx, "id=42&auth_date=1"); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Errorf("unsigned: %v", err)
+	}
+}
@@ -0,0 +1,288 @@
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"players_service/internal/domain/auth"
+)
+
+// OIDCConfig describes an OpenID Connect provider whose ID tokens are RS256
+// JWTs signed with keys published as a JWKS.
+type OIDCConfig struct {
+	Issuers   []string // accepted "iss" values
+	JWKSURL   string
+	Audiences []string      // our OAuth client ids, "aud" must name one of them
+	Leeway    time.Duration // clock skew allowed on exp and iat
+}
+
+// GoogleConfig is Google Sign-In for the given OAuth client ids.
+func GoogleConfig(clientIDs []string) OIDCConfig {
+	return OIDCConfig{
+		Issuers:   []string{"https://accounts.google.com", "accounts.google.com"},
+		JWKSURL:   "https://www.googleapis.com/oauth2/v3/certs",
+		Audiences: clientIDs,
+		Leeway:    time.Minute,
+	}
+}
+
+const (
+	// jwksDefaultTTL is used when the JWKS response has no max-age.
+	jwksDefaultTTL = time.Hour
+	// jwksMinRefresh limits refetching on unknown kids, which anyone can send.
+	jwksMinRefresh = time.Minute
+)
+
+// OIDC verifies ID tokens of one OpenID Connect provider. Signing keys are
+// fetched from the provider and cached for as long as it allows.
+type OIDC struct {
+	provider auth.Provider
+	cfg      OIDCConfig
+	client   *http.Client
+	clock    Clock
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+	expiresAt time.Time
+}
+
+func NewOIDC(provider auth.Provider, cfg OIDCConfig, client *http.Client, clock Clock) (*OIDC, error) {
+	if len(cfg.Audiences) == 0 {
+		return nil, fmt.Errorf("oidc %s: no client ids configured", provider)
+	}
+	if cfg.JWKSURL == "" || len(cfg.Issuers) == 0 {
+		return nil, fmt.Errorf("oidc %s: issuer and jwks url required", provider)
+	}
+	return &OIDC{provider: provider, cfg: cfg, client: client, clock: clock}, nil
+}
+
+type oidcHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type oidcClaims struct {
+	Iss           string          `json:"iss"`
+	Sub           string          `json:"sub"`
+	Aud           audience        `json:"aud"`
+	Exp           int64           `json:"exp"`
+	Iat           int64           `json:"iat"`
+	Email         string          `json:"email"`
+	EmailVerified json.RawMessage `json:"email_verified"` // bool, or "true" from some providers
+}
+
+// audience is a single string or a list of them.
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var one string
+	if err := json.Unmarshal(b, &one); err == nil {
+		*a = audience{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+func (o *OIDC) Verify(ctx context.Context, idToken string) (auth.ExternalIdentity, error) {
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return auth.ExternalIdentity{}, auth.ErrInvalidToken
+	}
+	var h oidcHeader
+	if err := decodeSegment(parts[0], &h); err != nil || h.Alg != "RS256" {
+		return auth.ExternalIdentity{}, auth.ErrInvalidToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return auth.ExternalIdentity{}, auth.ErrInvalidToken
+	}
+
+	key, err := o.key(ctx, h.Kid)
+	if err != nil {
+		return auth.ExternalIdentity{}, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig); err != nil {
+		return auth.ExternalIdentity{}, auth.ErrInvalidToken
+	}
+
+	var c oidcClaims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return auth.ExternalIdentity{}, auth.ErrInvalidToken
+	}
+	if err := o.checkClaims(c); err != nil {
+		return auth.ExternalIdentity{}, err
+	}
+
+	verified, _ := strconv.ParseBool(strings.Trim(string(c.EmailVerified), `"`))
+	return auth.ExternalIdentity{
+		Provider:      o.provider,
+		Subject:       c.Sub,
+		Email:         c.Email,
+		EmailVerified: verified,
+	}, nil
+}
+
+func (o *OIDC) checkClaims(c oidcClaims) error {
+	now := o.clock.Now()
+	if c.Sub == "" || !contains(o.cfg.Issuers, c.Iss) {
+		return auth.ErrInvalidToken
+	}
+	ok := false
+	for _, a := range c.Aud {
+		ok = ok || contains(o.cfg.Audiences, a)
+	}
+	if !ok {
+		return fmt.Errorf("%w: audience", auth.ErrInvalidToken)
+	}
+	if !now.Before(time.Unix(c.Exp, 0).Add(o.cfg.Leeway)) {
+		return fmt.Errorf("%w: expired", auth.ErrInvalidToken)
+	}
+	if time.Unix(c.Iat, 0).After(now.Add(o.cfg.Leeway)) {
+		return fmt.Errorf("%w: issued in the future", auth.ErrInvalidToken)
+	}
+	return nil
+}
+
+// key returns the signing key kid, refetching the JWKS when the cache is
+// stale or does not know the kid (the provider rotated its keys).
+func (o *OIDC) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	now := o.clock.Now()
+	k, ok := o.keys[kid]
+	if ok && now.Before(o.expiresAt) {
+		return k, nil
+	}
+	if ok || o.fetchedAt.IsZero() || now.Sub(o.fetchedAt) >= jwksMinRefresh {
+		if err := o.fetchKeys(ctx, now); err != nil {
+			if ok {
+				// keep working on the cached key while the provider is down
+				return k, nil
+			}
+			return nil, err
+		}
+		k, ok = o.keys[kid]
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown signing key", auth.ErrInvalidToken)
+	}
+	return k, nil
+}
+
+type jwks struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+// fetchKeys must be called with o.mu held.
+func (o *OIDC) fetchKeys(ctx context.Context, now time.Time) error {
+	o.fetchedAt = now
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.cfg.JWKSURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("oidc %s: fetch keys: %w", o.provider, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc %s: fetch keys: status %d", o.provider, resp.StatusCode)
+	}
+
+	var set jwks
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("oidc %s: decode keys: %w", o.provider, err)
+	}
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, jk := range set.Keys {
+		if jk.Kty != "RSA" || (jk.Use != "" && jk.Use != "sig") {
+			continue
+		}
+		k, err := rsaKey(jk.N, jk.E)
+		if err != nil {
+			return fmt.Errorf("oidc %s: key %s: %w", o.provider, jk.Kid, err)
+		}
+		keys[jk.Kid] = k
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("oidc %s: no signing keys published", o.provider)
+	}
+
+	o.keys = keys
+	o.expiresAt = now.Add(maxAge(resp.Header.Get("Cache-Control"), jwksDefaultTTL))
+	return nil
+}
+
+func rsaKey(n, e string) (*rsa.PublicKey, error) {
+	nb, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, err
+	}
+	eb, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, err
+	}
+	exp := new(big.Int).SetBytes(eb)
+	if !exp.IsInt64() || exp.Int64() < 3 || exp.Int64() > 1<<31-1 {
+		return nil, errors.New("bad exponent")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(nb), E: int(exp.Int64())}, nil
+}
+
+// maxAge reads max-age out of a Cache-Control header.
+func maxAge(cacheControl string, def time.Duration) time.Duration {
+	for _, d := range strings.Split(cacheControl, ",") {
+		v, ok := strings.CutPrefix(strings.TrimSpace(d), "max-age=")
+		if !ok {
+			continue
+		}
+		if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+			return time.Duration(secs) * time.Second
+		}
+	}
+	return def
+}
+
+func decodeSegment(seg string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
@@ -13,7 +13,13 @@ func NewUnitOfWork(db *sql.DB) *UnitOfWork {
 	return &UnitOfWork{db: db}
 }
 
+// WithinTx runs fn in a transaction. If ctx already carries one, fn joins it
+// and the outermost WithinTx decides on commit/rollback.
 func (u *UnitOfWork) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := TxFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
 	tx, err := u.db.BeginTx(ctx, &sql.TxOptions{})
 	if err != nil {
 		return err
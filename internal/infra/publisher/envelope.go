@@ -0,0 +1,31 @@
+package publisher
+
+import (
+	"encoding/json"
+	"time"
+
+	playeruc "players_service/internal/usecase/player"
+)
+
+// envelope is the wire format shared by all publishers.
+type envelope struct {
+	ID          string          `json:"id"`
+	Aggregate   string          `json:"aggregate"`
+	AggregateID string          `json:"aggregate_id"`
+	Type        string          `json:"type"`
+	Key         string          `json:"key"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   string          `json:"created_at"`
+}
+
+func marshal(msg playeruc.OutboxMessage) ([]byte, error) {
+	return json.Marshal(envelope{
+		ID:          msg.ID.String(),
+		Aggregate:   msg.Aggregate,
+		AggregateID: msg.AggregateID.String(),
+		Type:        msg.Type,
+		Key:         msg.Key,
+		Payload:     msg.Payload,
+		CreatedAt:   msg.CreatedAt.Format(time.RFC3339Nano),
+	})
+}
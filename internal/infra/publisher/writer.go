@@ -0,0 +1,50 @@
+package publisher
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+
+	playeruc "players_service/internal/usecase/player"
+)
+
+// Writer publishes messages as JSON lines. Handy for local runs and debugging.
+type Writer struct {
+	mu sync.Mutex
+	w  io.Writer
+	c  io.Closer
+}
+
+func NewWriter(w io.Writer) *Writer { return &Writer{w: w} }
+
+func NewStdout() *Writer { return NewWriter(os.Stdout) }
+
+// NewFile appends to path, creating it if needed.
+func NewFile(path string) (*Writer, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{w: f, c: f}, nil
+}
+
+func (p *Writer) Publish(_ context.Context, msg playeruc.OutboxMessage) error {
+	line, err := marshal(msg)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.w.Write(line)
+	return err
+}
+
+func (p *Writer) Close() error {
+	if p.c == nil {
+		return nil
+	}
+	return p.c.Close()
+}
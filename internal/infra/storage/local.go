@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/player"
+)
+
+var extensions = map[string]string{
+	"application/pdf": ".pdf",
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+}
+
+// Local keeps files on the local filesystem under root, one directory per
+// player. Refs are "<player id>/<file id><ext>", relative to root.
+type Local struct {
+	root string
+}
+
+func NewLocal(root string) (*Local, error) {
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return nil, err
+	}
+	return &Local{root: root}, nil
+}
+
+func (l *Local) Save(_ context.Context, playerID uuid.UUID, contentType string, r io.Reader) (string, error) {
+	ext, ok := extensions[contentType]
+	if !ok {
+		return "", errors.New("storage: unsupported content type " + contentType)
+	}
+	dir := filepath.Join(l.root, playerID.String())
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+
+	ref := path.Join(playerID.String(), uuid.NewString()+ext)
+	f, err := os.OpenFile(filepath.Join(l.root, filepath.FromSlash(ref)), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+	return ref, nil
+}
+
+func (l *Local) Owned(_ context.Context, playerID uuid.UUID, ref string) (bool, error) {
+	name, ok := l.resolve(ref)
+	if !ok || !strings.HasPrefix(ref, playerID.String()+"/") {
+		return false, nil
+	}
+	st, err := os.Stat(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return st.Mode().IsRegular(), nil
+}
+
+func (l *Local) Open(_ context.Context, ref string) (io.ReadCloser, string, error) {
+	name, ok := l.resolve(ref)
+	if !ok {
+		return nil, "", player.ErrNotFound
+	}
+	f, err := os.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, "", player.ErrNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	ct := mime.TypeByExtension(path.Ext(ref))
+	if ct == "" {
+		ct = "application/octet-stream"
+	}
+	return f, ct, nil
+}
+
+// resolve maps ref to a file name, refusing anything that would leave root.
+func (l *Local) resolve(ref string) (string, bool) {
+	if ref == "" || !fs.ValidPath(ref) || strings.Count(ref, "/") != 1 {
+		return "", false
+	}
+	return filepath.Join(l.root, filepath.FromSlash(ref)), true
+}
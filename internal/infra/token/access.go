@@ -0,0 +1,63 @@
+package token
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/auth"
+)
+
+const typAccess = "access"
+
+type accessClaims struct {
+	Typ string `json:"typ"`
+	Sub string `json:"sub"`
+	Sid string `json:"sid"`
+	Iat int64  `json:"iat"`
+	Exp int64  `json:"exp"`
+}
+
+// AccessTokens issues player access tokens as JWTs.
+type AccessTokens struct {
+	jwt *JWT
+}
+
+func NewAccessTokens(keys KeyProvider) *AccessTokens {
+	return &AccessTokens{jwt: NewJWT(keys)}
+}
+
+func (a *AccessTokens) Issue(c auth.AccessClaims) (string, error) {
+	return a.jwt.Sign(accessClaims{
+		Typ: typAccess,
+		Sub: c.PlayerID.String(),
+		Sid: c.SessionID.String(),
+		Iat: c.IssuedAt.Unix(),
+		Exp: c.ExpiresAt.Unix(),
+	})
+}
+
+// Parse verifies the signature only; expiry is up to the caller's clock.
+func (a *AccessTokens) Parse(tok string) (auth.AccessClaims, error) {
+	var c accessClaims
+	if err := a.jwt.Verify(tok, &c); err != nil {
+		return auth.AccessClaims{}, auth.ErrInvalidToken
+	}
+	if c.Typ != typAccess {
+		return auth.AccessClaims{}, auth.ErrInvalidToken
+	}
+	pid, err := uuid.Parse(c.Sub)
+	if err != nil {
+		return auth.AccessClaims{}, auth.ErrInvalidToken
+	}
+	sid, err := uuid.Parse(c.Sid)
+	if err != nil {
+		return auth.AccessClaims{}, auth.ErrInvalidToken
+	}
+	return auth.AccessClaims{
+		PlayerID:  pid,
+		SessionID: sid,
+		IssuedAt:  time.Unix(c.Iat, 0).UTC(),
+		ExpiresAt: time.Unix(c.Exp, 0).UTC(),
+	}, nil
+}
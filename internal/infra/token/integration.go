@@ -0,0 +1,63 @@
+package token
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/auth"
+)
+
+const typIntegration = "integration"
+
+type integrationClaims struct {
+	Typ string `json:"typ"`
+	Sub string `json:"sub"`
+	Aud string `json:"aud"`
+	Cur string `json:"cur"`
+	Iat int64  `json:"iat"`
+	Exp int64  `json:"exp"`
+}
+
+// IntegrationTokens issues tokens game providers use to identify players.
+// They should be given their own keys, apart from the access token keys.
+type IntegrationTokens struct {
+	jwt *JWT
+}
+
+func NewIntegrationTokens(keys KeyProvider) *IntegrationTokens {
+	return &IntegrationTokens{jwt: NewJWT(keys)}
+}
+
+func (t *IntegrationTokens) Issue(c auth.IntegrationClaims) (string, error) {
+	return t.jwt.Sign(integrationClaims{
+		Typ: typIntegration,
+		Sub: c.PlayerID.String(),
+		Aud: c.Provider,
+		Cur: c.Currency,
+		Iat: c.IssuedAt.Unix(),
+		Exp: c.ExpiresAt.Unix(),
+	})
+}
+
+// Parse verifies the signature only; expiry is up to the caller's clock.
+func (t *IntegrationTokens) Parse(tok string) (auth.IntegrationClaims, error) {
+	var c integrationClaims
+	if err := t.jwt.Verify(tok, &c); err != nil {
+		return auth.IntegrationClaims{}, auth.ErrInvalidToken
+	}
+	if c.Typ != typIntegration {
+		return auth.IntegrationClaims{}, auth.ErrInvalidToken
+	}
+	pid, err := uuid.Parse(c.Sub)
+	if err != nil {
+		return auth.IntegrationClaims{}, auth.ErrInvalidToken
+	}
+	return auth.IntegrationClaims{
+		PlayerID:  pid,
+		Currency:  c.Cur,
+		Provider:  c.Aud,
+		IssuedAt:  time.Unix(c.Iat, 0).UTC(),
+		ExpiresAt: time.Unix(c.Exp, 0).UTC(),
+	}, nil
+}
@@ -0,0 +1,91 @@
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var ErrInvalid = errors.New("token: invalid")
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+// JWT signs and verifies compact HS256 JWTs. It only deals with the signature;
+// claim semantics (expiry, audience) are checked by the callers.
+type JWT struct {
+	keys KeyProvider
+}
+
+func NewJWT(keys KeyProvider) *JWT { return &JWT{keys: keys} }
+
+func (j *JWT) Sign(claims any) (string, error) {
+	k, err := j.keys.Current()
+	if err != nil {
+		return "", err
+	}
+
+	h, err := json.Marshal(header{Alg: "HS256", Typ: "JWT", Kid: k.ID})
+	if err != nil {
+		return "", err
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signing := b64(h) + "." + b64(c)
+	return signing + "." + b64(sign(k.Secret, signing)), nil
+}
+
+// Verify checks the signature and decodes the payload into claims.
+func (j *JWT) Verify(tok string, claims any) error {
+	parts := strings.Split(tok, ".")
+	if len(parts) != 3 {
+		return ErrInvalid
+	}
+
+	rawHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return ErrInvalid
+	}
+	var h header
+	if err := json.Unmarshal(rawHeader, &h); err != nil || h.Alg != "HS256" {
+		return ErrInvalid
+	}
+	k, ok := j.keys.Lookup(h.Kid)
+	if !ok {
+		return ErrInvalid
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return ErrInvalid
+	}
+	if !hmac.Equal(sig, sign(k.Secret, parts[0]+"."+parts[1])) {
+		return ErrInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ErrInvalid
+	}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return ErrInvalid
+	}
+	return nil
+}
+
+func sign(secret []byte, s string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(s))
+	return mac.Sum(nil)
+}
+
+func b64(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
@@ -0,0 +1,88 @@
+package token
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Key is a symmetric signing key. ID goes into the token header ("kid").
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// KeyProvider supplies signing keys. Tokens are signed with Current and verified
+// with whatever key their kid points at, so a rotated-out key keeps validating
+// in-flight tokens for as long as Lookup still returns it.
+type KeyProvider interface {
+	Current() (Key, error)
+	Lookup(id string) (Key, bool)
+}
+
+var ErrNoKeys = errors.New("token: no signing keys")
+
+// StaticKeys is an in-memory KeyProvider. The first key is the current one.
+// Keys are rotated through the config: put the new key first and keep the
+// old one after it until the tokens it signed have expired.
+type StaticKeys struct {
+	keys []Key
+}
+
+func NewStaticKeys(keys ...Key) *StaticKeys {
+	return &StaticKeys{keys: keys}
+}
+
+// ParseKeys reads "kid:base64secret,kid2:base64secret2", current key first.
+func ParseKeys(spec string) (*StaticKeys, error) {
+	var keys []Key
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		id, enc, ok := strings.Cut(item, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("token: bad key entry %q", item)
+		}
+		secret, err := base64.StdEncoding.DecodeString(enc)
+		if err != nil {
+			return nil, fmt.Errorf("token: key %s: %w", id, err)
+		}
+		if len(secret) < 32 {
+			return nil, fmt.Errorf("token: key %s must be at least 32 bytes", id)
+		}
+		keys = append(keys, Key{ID: id, Secret: secret})
+	}
+	if len(keys) == 0 {
+		return nil, ErrNoKeys
+	}
+	return NewStaticKeys(keys...), nil
+}
+
+// RandomKey makes a throwaway key, e.g. for local runs without configuration.
+func RandomKey(id string) (Key, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return Key{}, err
+	}
+	return Key{ID: id, Secret: secret}, nil
+}
+
+func (s *StaticKeys) Current() (Key, error) {
+	if len(s.keys) == 0 {
+		return Key{}, ErrNoKeys
+	}
+	return s.keys[0], nil
+}
+
+func (s *StaticKeys) Lookup(id string) (Key, bool) {
+	for _, k := range s.keys {
+		if k.ID == id {
+			return k, true
+		}
+	}
+	return Key{}, false
+}
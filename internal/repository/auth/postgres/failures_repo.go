@@ -0,0 +1,56 @@
+package authpg
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/auth"
+)
+
+type FailuresRepo struct {
+	db *sql.DB
+}
+
+func NewFailures(db *sql.DB) *FailuresRepo { return &FailuresRepo{db: db} }
+
+func (r *FailuresRepo) Record(ctx context.Context, f auth.LoginFailure) error {
+	ex := pickExecutor(ctx, r.db)
+
+	var playerID any
+	if f.PlayerID != uuid.Nil {
+		playerID = f.PlayerID
+	}
+	const q = `INSERT INTO login_failures (player_id, ip, created_at) VALUES ($1,$2,$3)`
+	_, err := ex.ExecContext(ctx, q, playerID, nullIP(f.IP), f.At)
+	return err
+}
+
+func (r *FailuresRepo) CountForPlayer(ctx context.Context, playerID uuid.UUID, since time.Time) (int, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `SELECT count(*) FROM login_failures WHERE player_id = $1 AND created_at >= $2`
+	var n int
+	err := ex.QueryRowContext(ctx, q, playerID, since).Scan(&n)
+	return n, err
+}
+
+func (r *FailuresRepo) CountForIP(ctx context.Context, ip net.IP, since time.Time) (int, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `SELECT count(*) FROM login_failures WHERE ip = $1 AND created_at >= $2`
+	var n int
+	err := ex.QueryRowContext(ctx, q, ip.String(), since).Scan(&n)
+	return n, err
+}
+
+// ClearForPlayer forgets the player's failures, after a successful login.
+func (r *FailuresRepo) ClearForPlayer(ctx context.Context, playerID uuid.UUID) error {
+	ex := pickExecutor(ctx, r.db)
+
+	_, err := ex.ExecContext(ctx, `DELETE FROM login_failures WHERE player_id = $1`, playerID)
+	return err
+}
@@ -0,0 +1,119 @@
+package authpg
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/auth"
+)
+
+type LoginsRepo struct {
+	db *sql.DB
+}
+
+func NewLogins(db *sql.DB) *LoginsRepo { return &LoginsRepo{db: db} }
+
+func (r *LoginsRepo) Append(ctx context.Context, l auth.LoginRecord) error {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+INSERT INTO player_logins (id, player_id, ip, user_agent, method, outcome, created_at)
+VALUES ($1,$2,$3,$4,$5,$6,$7)
+`
+	_, err := ex.ExecContext(ctx, q,
+		l.ID, l.PlayerID, nullIP(l.IP), nullStr(l.UserAgent), string(l.Method), string(l.Outcome), l.CreatedAt,
+	)
+	return err
+}
+
+func (r *LoginsRepo) List(ctx context.Context, f auth.LoginFilter) ([]auth.LoginRecord, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	args := []any{f.PlayerID}
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	where := []string{"player_id = $1"}
+	if f.After != nil {
+		where = append(where, "(created_at, id) < ("+arg(f.After.CreatedAt)+", "+arg(f.After.ID)+")")
+	}
+
+	q := `
+SELECT id, player_id, ip, user_agent, method, outcome, created_at
+  FROM player_logins
+ WHERE ` + strings.Join(where, " AND ") + `
+ ORDER BY created_at DESC, id DESC
+ LIMIT ` + arg(f.Limit)
+
+	rows, err := ex.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]auth.LoginRecord, 0, f.Limit)
+	for rows.Next() {
+		var (
+			l               auth.LoginRecord
+			ip, ua          sql.NullString
+			method, outcome string
+		)
+		if err := rows.Scan(&l.ID, &l.PlayerID, &ip, &ua, &method, &outcome, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+		if ip.Valid {
+			l.IP = net.ParseIP(ip.String)
+		}
+		l.UserAgent = ua.String
+		l.Method = auth.LoginMethod(method)
+		l.Outcome = auth.LoginOutcome(outcome)
+		items = append(items, l)
+	}
+	return items, rows.Err()
+}
+
+// SharedIPs lists other players who logged in from an IP the player logged
+// in from, most recently seen first. Only successful logins count on either
+// side: anyone can fail a login as anyone from anywhere.
+func (r *LoginsRepo) SharedIPs(ctx context.Context, playerID uuid.UUID, limit int) ([]auth.SharedIP, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+SELECT o.player_id, o.ip, count(*), max(o.created_at)
+  FROM player_logins o
+  JOIN (SELECT DISTINCT ip FROM player_logins
+         WHERE player_id = $1 AND outcome = $3 AND ip IS NOT NULL) m
+    ON m.ip = o.ip
+ WHERE o.player_id <> $1
+   AND o.outcome = $3
+ GROUP BY o.player_id, o.ip
+ ORDER BY max(o.created_at) DESC, o.player_id
+ LIMIT $2
+`
+	rows, err := ex.QueryContext(ctx, q, playerID, limit, string(auth.LoginSucceeded))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []auth.SharedIP{}
+	for rows.Next() {
+		var (
+			s  auth.SharedIP
+			ip string
+		)
+		if err := rows.Scan(&s.PlayerID, &ip, &s.Logins, &s.LastLoginAt); err != nil {
+			return nil, err
+		}
+		s.IP = net.ParseIP(ip)
+		items = append(items, s)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,51 @@
+package authpg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/auth"
+	"players_service/internal/domain/player"
+)
+
+type PasswordsRepo struct {
+	db *sql.DB
+}
+
+func NewPasswords(db *sql.DB) *PasswordsRepo { return &PasswordsRepo{db: db} }
+
+func (r *PasswordsRepo) Get(ctx context.Context, playerID uuid.UUID) (auth.PasswordCredential, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+SELECT player_id, hash, created_at, updated_at
+  FROM player_passwords
+ WHERE player_id = $1
+`
+	var c auth.PasswordCredential
+	err := ex.QueryRowContext(ctx, q, playerID).Scan(&c.PlayerID, &c.Hash, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return auth.PasswordCredential{}, player.ErrNotFound
+		}
+		return auth.PasswordCredential{}, err
+	}
+	return c, nil
+}
+
+func (r *PasswordsRepo) Upsert(ctx context.Context, c auth.PasswordCredential) error {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+INSERT INTO player_passwords (player_id, hash, created_at, updated_at)
+VALUES ($1,$2,$3,$4)
+ON CONFLICT (player_id) DO UPDATE
+   SET hash = EXCLUDED.hash,
+       updated_at = EXCLUDED.updated_at
+`
+	_, err := ex.ExecContext(ctx, q, c.PlayerID, c.Hash, c.CreatedAt, c.UpdatedAt)
+	return err
+}
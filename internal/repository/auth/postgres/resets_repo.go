@@ -0,0 +1,92 @@
+package authpg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/auth"
+	"players_service/internal/domain/player"
+)
+
+type ResetsRepo struct {
+	db *sql.DB
+}
+
+func NewResets(db *sql.DB) *ResetsRepo { return &ResetsRepo{db: db} }
+
+func (r *ResetsRepo) Create(ctx context.Context, pr auth.PasswordReset) error {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+INSERT INTO password_resets (id, player_id, token_hash, ip, created_at, expires_at, used_at)
+VALUES ($1,$2,$3,$4,$5,$6,NULL)
+`
+	_, err := ex.ExecContext(ctx, q, pr.ID, pr.PlayerID, pr.TokenHash, nullIP(pr.IP), pr.CreatedAt, pr.ExpiresAt)
+	return err
+}
+
+func (r *ResetsRepo) GetByTokenHash(ctx context.Context, hash string) (auth.PasswordReset, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+SELECT id, player_id, token_hash, ip, created_at, expires_at, used_at
+  FROM password_resets
+ WHERE token_hash = $1
+`
+	var (
+		pr     auth.PasswordReset
+		ip     sql.NullString
+		usedAt sql.NullTime
+	)
+	err := ex.QueryRowContext(ctx, q, hash).Scan(
+		&pr.ID, &pr.PlayerID, &pr.TokenHash, &ip, &pr.CreatedAt, &pr.ExpiresAt, &usedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return auth.PasswordReset{}, player.ErrNotFound
+		}
+		return auth.PasswordReset{}, err
+	}
+	if ip.Valid {
+		pr.IP = net.ParseIP(ip.String)
+	}
+	if usedAt.Valid {
+		pr.UsedAt = usedAt.Time
+	}
+	return pr, nil
+}
+
+// MarkUsed reports false when the reset was already used.
+func (r *ResetsRepo) MarkUsed(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `UPDATE password_resets SET used_at = $2 WHERE id = $1 AND used_at IS NULL`
+	res, err := ex.ExecContext(ctx, q, id, at)
+	if err != nil {
+		return false, err
+	}
+	aff, _ := res.RowsAffected()
+	return aff > 0, nil
+}
+
+type PasswordAuditRepo struct {
+	db *sql.DB
+}
+
+func NewPasswordAudit(db *sql.DB) *PasswordAuditRepo { return &PasswordAuditRepo{db: db} }
+
+func (r *PasswordAuditRepo) Append(ctx context.Context, a auth.PasswordAudit) error {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+INSERT INTO password_audit (id, player_id, action, ip, created_at)
+VALUES ($1,$2,$3,$4,$5)
+`
+	_, err := ex.ExecContext(ctx, q, a.ID, a.PlayerID, a.Action, nullIP(a.IP), a.CreatedAt)
+	return err
+}
@@ -0,0 +1,159 @@
+package authpg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/auth"
+	"players_service/internal/domain/player"
+)
+
+type SessionsRepo struct {
+	db *sql.DB
+}
+
+func NewSessions(db *sql.DB) *SessionsRepo { return &SessionsRepo{db: db} }
+
+const selectSessionColumns = `
+SELECT id, player_id, family_id, refresh_hash, ip, user_agent,
+       created_at, expires_at, revoked_at, revoke_reason
+  FROM player_sessions
+`
+
+func (r *SessionsRepo) Create(ctx context.Context, s auth.Session) error {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+INSERT INTO player_sessions (
+  id, player_id, family_id, refresh_hash, ip, user_agent,
+  created_at, expires_at, revoked_at, revoke_reason
+) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,NULL,NULL)
+`
+	_, err := ex.ExecContext(ctx, q,
+		s.ID, s.PlayerID, s.FamilyID, s.RefreshHash, nullIP(s.IP), nullStr(s.UserAgent),
+		s.CreatedAt, s.ExpiresAt,
+	)
+	return err
+}
+
+func (r *SessionsRepo) GetByID(ctx context.Context, id uuid.UUID) (auth.Session, error) {
+	ex := pickExecutor(ctx, r.db)
+	return scanSession(ex.QueryRowContext(ctx, selectSessionColumns+` WHERE id = $1`, id))
+}
+
+func (r *SessionsRepo) GetByRefreshHash(ctx context.Context, hash string) (auth.Session, error) {
+	ex := pickExecutor(ctx, r.db)
+	return scanSession(ex.QueryRowContext(ctx, selectSessionColumns+` WHERE refresh_hash = $1`, hash))
+}
+
+func (r *SessionsRepo) Revoke(ctx context.Context, id uuid.UUID, at time.Time, reason string) (bool, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+UPDATE player_sessions
+   SET revoked_at = $2, revoke_reason = $3
+ WHERE id = $1 AND revoked_at IS NULL
+`
+	res, err := ex.ExecContext(ctx, q, id, at, reason)
+	if err != nil {
+		return false, err
+	}
+	aff, _ := res.RowsAffected()
+	return aff > 0, nil
+}
+
+func (r *SessionsRepo) RevokeFamily(ctx context.Context, familyID uuid.UUID, at time.Time, reason string) (int, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+UPDATE player_sessions
+   SET revoked_at = $2, revoke_reason = $3
+ WHERE family_id = $1 AND revoked_at IS NULL
+`
+	res, err := ex.ExecContext(ctx, q, familyID, at, reason)
+	if err != nil {
+		return 0, err
+	}
+	aff, _ := res.RowsAffected()
+	return int(aff), nil
+}
+
+func (r *SessionsRepo) RevokeAllForPlayer(ctx context.Context, playerID uuid.UUID, at time.Time, reason string) (int, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+UPDATE player_sessions
+   SET revoked_at = $2, revoke_reason = $3
+ WHERE player_id = $1 AND revoked_at IS NULL
+`
+	res, err := ex.ExecContext(ctx, q, playerID, at, reason)
+	if err != nil {
+		return 0, err
+	}
+	aff, _ := res.RowsAffected()
+	return int(aff), nil
+}
+
+// RevokeOthers ends every active session of the player except keepID.
+func (r *SessionsRepo) RevokeOthers(ctx context.Context, playerID, keepID uuid.UUID, at time.Time, reason string) (int, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+UPDATE player_sessions
+   SET revoked_at = $3, revoke_reason = $4
+ WHERE player_id = $1 AND id <> $2 AND revoked_at IS NULL
+`
+	res, err := ex.ExecContext(ctx, q, playerID, keepID, at, reason)
+	if err != nil {
+		return 0, err
+	}
+	aff, _ := res.RowsAffected()
+	return int(aff), nil
+}
+
+func scanSession(row *sql.Row) (auth.Session, error) {
+	var (
+		s         auth.Session
+		ip, ua    sql.NullString
+		revokedAt sql.NullTime
+		reason    sql.NullString
+	)
+	err := row.Scan(
+		&s.ID, &s.PlayerID, &s.FamilyID, &s.RefreshHash, &ip, &ua,
+		&s.CreatedAt, &s.ExpiresAt, &revokedAt, &reason,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return auth.Session{}, player.ErrNotFound
+		}
+		return auth.Session{}, err
+	}
+	if ip.Valid {
+		s.IP = net.ParseIP(ip.String)
+	}
+	s.UserAgent = ua.String
+	if revokedAt.Valid {
+		s.RevokedAt = revokedAt.Time
+	}
+	s.RevokeReason = reason.String
+	return s, nil
+}
+
+func nullStr(s string) sql.NullString {
+	if s == "" {
+		return sql.NullString{Valid: false}
+	}
+	return sql.NullString{String: s, Valid: true}
+}
+
+func nullIP(ip net.IP) any {
+	if len(ip) == 0 {
+		return nil
+	}
+	return ip.String()
+}
@@ -0,0 +1,124 @@
+package authpg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/auth"
+	"players_service/internal/domain/player"
+	"players_service/internal/infra/postgres"
+)
+
+type SocialRepo struct {
+	db *sql.DB
+}
+
+func NewSocial(db *sql.DB) *SocialRepo { return &SocialRepo{db: db} }
+
+const selectSocialColumns = `
+SELECT provider, subject, player_id, email, linked_at
+  FROM social_accounts
+`
+
+func (r *SocialRepo) Get(ctx context.Context, provider auth.Provider, subject string) (auth.SocialAccount, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	a, err := scanSocial(ex.QueryRowContext(ctx, selectSocialColumns+` WHERE provider = $1 AND subject = $2`, string(provider), subject))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return auth.SocialAccount{}, player.ErrNotFound
+		}
+		return auth.SocialAccount{}, err
+	}
+	return a, nil
+}
+
+func (r *SocialRepo) ListByPlayer(ctx context.Context, playerID uuid.UUID) ([]auth.SocialAccount, error) {
+	return r.list(ctx, selectSocialColumns+` WHERE player_id = $1 ORDER BY linked_at`, playerID)
+}
+
+// LockByPlayer locks the player's accounts FOR UPDATE. A concurrent unlink
+// blocks until this transaction ends and then no longer sees what it deleted.
+func (r *SocialRepo) LockByPlayer(ctx context.Context, playerID uuid.UUID) ([]auth.SocialAccount, error) {
+	if _, ok := postgres.TxFromContext(ctx); !ok {
+		return nil, errors.New("social accounts lock outside of a transaction")
+	}
+	return r.list(ctx, selectSocialColumns+` WHERE player_id = $1 ORDER BY linked_at FOR UPDATE`, playerID)
+}
+
+func (r *SocialRepo) list(ctx context.Context, q string, args ...any) ([]auth.SocialAccount, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	rows, err := ex.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []auth.SocialAccount{}
+	for rows.Next() {
+		a, err := scanSocial(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, a)
+	}
+	return items, rows.Err()
+}
+
+// Create returns ErrConflict when the identity is linked already, or the
+// player has an account at that provider.
+func (r *SocialRepo) Create(ctx context.Context, a auth.SocialAccount) error {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+INSERT INTO social_accounts (provider, subject, player_id, email, linked_at)
+VALUES ($1,$2,$3,$4,$5)
+ON CONFLICT DO NOTHING
+`
+	res, err := ex.ExecContext(ctx, q, string(a.Provider), a.Subject, a.PlayerID, nullStr(a.Email), a.LinkedAt)
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return player.ErrConflict
+	}
+	return nil
+}
+
+func (r *SocialRepo) Delete(ctx context.Context, playerID uuid.UUID, provider auth.Provider) error {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `DELETE FROM social_accounts WHERE player_id = $1 AND provider = $2`
+	res, err := ex.ExecContext(ctx, q, playerID, string(provider))
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return player.ErrNotFound
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSocial(row rowScanner) (auth.SocialAccount, error) {
+	var (
+		a        auth.SocialAccount
+		provider string
+		email    sql.NullString
+	)
+	if err := row.Scan(&provider, &a.Subject, &a.PlayerID, &email, &a.LinkedAt); err != nil {
+		return auth.SocialAccount{}, err
+	}
+	a.Provider = auth.Provider(provider)
+	a.Email = email.String
+	return a, nil
+}
@@ -3,8 +3,14 @@ package outboxpg
 import (
 	"context"
 	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 
 	"players_service/internal/infra/postgres"
+	outboxuc "players_service/internal/usecase/outbox"
 	playeruc "players_service/internal/usecase/player"
 )
 
@@ -18,6 +24,7 @@ func New(db *sql.DB) *Repo { return &Repo{db: db} }
 
 type executor interface {
 	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
 	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 }
 
@@ -41,3 +48,89 @@ INSERT INTO outbox (
 	)
 	return err
 }
+
+// Claim sets the lease in the same statement that picks the messages, so
+// no lock outlives it. A message waits while an earlier one of its key is
+// unpublished, whether that one is leased, in backoff or not yet claimed.
+func (r *Repo) Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]outboxuc.Pending, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+UPDATE outbox
+   SET locked_until = $2
+ WHERE id IN (
+         SELECT o.id
+           FROM outbox o
+          WHERE o.published_at IS NULL
+            AND (o.next_attempt_at IS NULL OR o.next_attempt_at <= $1)
+            AND (o.locked_until IS NULL OR o.locked_until <= $1)
+            AND NOT EXISTS (
+                  SELECT 1
+                    FROM outbox e
+                   WHERE e.key = o.key
+                     AND e.published_at IS NULL
+                     AND (e.created_at, e.id) < (o.created_at, o.id)
+                )
+          ORDER BY o.created_at, o.id
+          LIMIT $3
+          FOR UPDATE SKIP LOCKED
+       )
+RETURNING id, aggregate, aggregate_id, type, key, payload, created_at, attempts
+`
+	rows, err := ex.QueryContext(ctx, q, now, leaseUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []outboxuc.Pending
+	for rows.Next() {
+		var p outboxuc.Pending
+		m := &p.Message
+		if err := rows.Scan(&m.ID, &m.Aggregate, &m.AggregateID, &m.Type, &m.Key, &m.Payload, &m.CreatedAt, &p.Attempts); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// RETURNING does not keep the subquery order
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i].Message, out[j].Message
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	})
+	return out, nil
+}
+
+func (r *Repo) MarkPublished(ctx context.Context, ids []uuid.UUID, at time.Time) error {
+	ex := pickExecutor(ctx, r.db)
+
+	arr := make([]string, 0, len(ids))
+	for _, id := range ids {
+		arr = append(arr, id.String())
+	}
+
+	const q = `
+UPDATE outbox
+   SET published_at = $2, last_error = NULL, next_attempt_at = NULL, locked_until = NULL
+ WHERE id = ANY($1::uuid[])
+`
+	_, err := ex.ExecContext(ctx, q, pq.Array(arr), at)
+	return err
+}
+
+func (r *Repo) MarkFailed(ctx context.Context, id uuid.UUID, attempts int, lastErr string, nextAttemptAt time.Time) error {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+UPDATE outbox
+   SET attempts = $2, last_error = $3, next_attempt_at = $4, locked_until = NULL
+ WHERE id = $1
+`
+	_, err := ex.ExecContext(ctx, q, id, attempts, lastErr, nextAttemptAt)
+	return err
+}
@@ -0,0 +1,117 @@
+package playerpg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/player"
+)
+
+// AddCredential returns ErrConflict when the value is taken, by any player.
+func (r *Repo) AddCredential(ctx context.Context, c player.Credential) error {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+INSERT INTO player_credentials (id, player_id, kind, value, is_primary, verified_at, created_at)
+VALUES ($1,$2,$3,$4,$5,$6,$7)
+ON CONFLICT DO NOTHING
+`
+	res, err := ex.ExecContext(ctx, q,
+		c.ID, c.PlayerID, int16(c.Kind), c.Value, c.Primary, nullTime(c.VerifiedAt), c.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return player.ErrConflict
+	}
+	return nil
+}
+
+func (r *Repo) ListCredentials(ctx context.Context, playerID uuid.UUID) ([]player.Credential, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+SELECT id, player_id, kind, value, is_primary, verified_at, created_at
+  FROM player_credentials
+ WHERE player_id = $1
+ ORDER BY kind, is_primary DESC, created_at
+`
+	rows, err := ex.QueryContext(ctx, q, playerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []player.Credential{}
+	for rows.Next() {
+		var (
+			c        player.Credential
+			kind     int16
+			verified sql.NullTime
+		)
+		if err := rows.Scan(&c.ID, &c.PlayerID, &kind, &c.Value, &c.Primary, &verified, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		c.Kind = player.CredentialKind(kind)
+		if verified.Valid {
+			c.VerifiedAt = verified.Time
+		}
+		items = append(items, c)
+	}
+	return items, rows.Err()
+}
+
+// playerIDByCredential finds the owner of an email or phone, primary or not.
+func playerIDByCredential(ctx context.Context, ex executor, kind player.CredentialKind, value string) (uuid.UUID, error) {
+	const q = `SELECT player_id FROM player_credentials WHERE kind = $1 AND value = $2`
+
+	var id uuid.UUID
+	err := ex.QueryRowContext(ctx, q, int16(kind), value).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, player.ErrNotFound
+		}
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
+// syncPrimary makes value the primary credential of its kind for the player,
+// replacing the previous one. An empty value only drops the primary.
+// A value owned by another player yields ErrConflict.
+func syncPrimary(ctx context.Context, ex executor, playerID uuid.UUID, kind player.CredentialKind, value string, verifiedAt, now time.Time) error {
+	const drop = `
+DELETE FROM player_credentials
+ WHERE player_id = $1 AND kind = $2 AND is_primary AND value IS DISTINCT FROM $3
+`
+	if _, err := ex.ExecContext(ctx, drop, playerID, int16(kind), nullStr(value)); err != nil {
+		return err
+	}
+	if value == "" {
+		return nil
+	}
+
+	const upsert = `
+INSERT INTO player_credentials (id, player_id, kind, value, is_primary, verified_at, created_at)
+VALUES ($1,$2,$3,$4,TRUE,$5,$6)
+ON CONFLICT (kind, value) DO UPDATE
+   SET is_primary = TRUE,
+       verified_at = EXCLUDED.verified_at
+ WHERE player_credentials.player_id = EXCLUDED.player_id
+`
+	res, err := ex.ExecContext(ctx, upsert, uuid.New(), playerID, int16(kind), value, nullTime(verifiedAt), now)
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return player.ErrConflict
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package playerpg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/player"
+)
+
+type DocumentsRepo struct {
+	db *sql.DB
+}
+
+func NewDocuments(db *sql.DB) *DocumentsRepo { return &DocumentsRepo{db: db} }
+
+const selectDocument = `
+SELECT id, player_id, type, file_ref, status, reason, version, created_at, updated_at
+  FROM player_documents
+`
+
+func (r *DocumentsRepo) Create(ctx context.Context, d *player.Document) error {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+INSERT INTO player_documents (
+  id, player_id, type, file_ref, status, reason, version, created_at, updated_at
+) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+`
+	_, err := ex.ExecContext(ctx, q,
+		d.ID, d.PlayerID, int16(d.Type), d.FileRef, int16(d.Status), nullStr(d.Reason), d.Version, d.CreatedAt, d.UpdatedAt,
+	)
+	return err
+}
+
+func (r *DocumentsRepo) GetByID(ctx context.Context, id uuid.UUID) (*player.Document, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	d, err := scanDocument(ex.QueryRowContext(ctx, selectDocument+` WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, player.ErrNotFound
+	}
+	return d, err
+}
+
+// Update saves a reviewed document, optimistic lock by version.
+func (r *DocumentsRepo) Update(ctx context.Context, d *player.Document) error {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+UPDATE player_documents
+   SET status=$2, reason=$3, version=$4, updated_at=$5
+ WHERE id=$1 AND version=$6
+`
+	res, err := ex.ExecContext(ctx, q,
+		d.ID, int16(d.Status), nullStr(d.Reason), d.Version, d.UpdatedAt, d.Version-1,
+	)
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return player.ErrConflict
+	}
+	return nil
+}
+
+// ListByPlayer returns documents newest first.
+func (r *DocumentsRepo) ListByPlayer(ctx context.Context, playerID uuid.UUID) ([]*player.Document, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	rows, err := ex.QueryContext(ctx, selectDocument+` WHERE player_id = $1 ORDER BY created_at DESC, id DESC`, playerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*player.Document{}
+	for rows.Next() {
+		d, err := scanDocument(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *DocumentsRepo) AppendReview(ctx context.Context, rv player.DocumentReview) error {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+INSERT INTO player_document_reviews (
+  id, document_id, player_id, from_status, to_status, reason, actor_type, created_at
+) VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+`
+	_, err := ex.ExecContext(ctx, q,
+		rv.ID, rv.DocumentID, rv.PlayerID, int16(rv.From), int16(rv.To), nullStr(rv.Reason), int16(rv.ActorType), rv.CreatedAt,
+	)
+	return err
+}
+
+func scanDocument(row rowScanner) (*player.Document, error) {
+	var (
+		d           player.Document
+		typ, status int16
+		reason      sql.NullString
+	)
+	if err := row.Scan(&d.ID, &d.PlayerID, &typ, &d.FileRef, &status, &reason, &d.Version, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		return nil, err
+	}
+	d.Type = player.DocumentType(typ)
+	d.Status = player.DocumentStatus(status)
+	d.Reason = reason.String
+	return &d, nil
+}
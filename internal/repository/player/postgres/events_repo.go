@@ -3,6 +3,8 @@ package playerpg
 import (
 	"context"
 	"database/sql"
+	"strconv"
+	"strings"
 
 	"players_service/internal/domain/player"
 )
@@ -18,11 +20,68 @@ func (r *EventsRepo) Append(ctx context.Context, ev player.PlayerStatusEvent) er
 
 	const q = `
 INSERT INTO player_status_events (
-  id, player_id, from_status, to_status, reason, actor_type, created_at
-) VALUES ($1,$2,$3,$4,$5,$6,$7)
+  id, player_id, from_status, to_status, reason, actor_type, until, created_at
+) VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
 `
 	_, err := ex.ExecContext(ctx, q,
-		ev.ID, ev.PlayerID, int16(ev.From), int16(ev.To), ev.Reason, int16(ev.ActorType), ev.CreatedAt,
+		ev.ID, ev.PlayerID, int16(ev.From), int16(ev.To), ev.Reason, int16(ev.ActorType), nullTime(ev.Until), ev.CreatedAt,
 	)
 	return err
 }
+
+func (r *EventsRepo) List(ctx context.Context, f player.StatusEventFilter) ([]player.PlayerStatusEvent, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	args := []any{f.PlayerID}
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	where := []string{"player_id = $1"}
+	if f.ActorType != 0 {
+		where = append(where, "actor_type = "+arg(int16(f.ActorType)))
+	}
+	if f.ToStatus != player.StatusUnknown {
+		where = append(where, "to_status = "+arg(int16(f.ToStatus)))
+	}
+	if f.After != nil {
+		where = append(where, "(created_at, id) < ("+arg(f.After.CreatedAt)+", "+arg(f.After.ID)+")")
+	}
+
+	q := `
+SELECT id, player_id, from_status, to_status, reason, actor_type, until, created_at
+  FROM player_status_events
+ WHERE ` + strings.Join(where, " AND ") + `
+ ORDER BY created_at DESC, id DESC
+ LIMIT ` + arg(f.Limit)
+
+	rows, err := ex.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]player.PlayerStatusEvent, 0, f.Limit)
+	for rows.Next() {
+		var (
+			ev              player.PlayerStatusEvent
+			from, to, actor int16
+			until           sql.NullTime
+		)
+		if err := rows.Scan(&ev.ID, &ev.PlayerID, &from, &to, &ev.Reason, &actor, &until, &ev.CreatedAt); err != nil {
+			return nil, err
+		}
+		if until.Valid {
+			ev.Until = until.Time
+		}
+		ev.From = player.Status(from)
+		ev.To = player.Status(to)
+		ev.ActorType = player.ActorType(actor)
+		items = append(items, ev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -5,10 +5,14 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 
 	"players_service/internal/domain/player"
 )
@@ -19,25 +23,83 @@ type Repo struct {
 
 func New(db *sql.DB) *Repo { return &Repo{db: db} }
 
-func (r *Repo) GetByID(ctx context.Context, id uuid.UUID) (*player.Player, error) {
-	ex := pickExecutor(ctx, r.db)
-
-	const q = `
-SELECT id, email, phone, status, status_reason,
+const selectPlayerColumns = `
+SELECT id, email, phone, email_verified_at, phone_verified_at,
+       status, status_reason, status_until, kyc_level,
+       exclusion_kind, excluded_until,
        country_code, locale, time_zone,
        first_name, last_name, birth_date, gender,
-       registration_ip, registered_at, last_login_at,
+       registration_ip, registration_ip_country, registered_at, last_login_at,
        metadata, version, created_at, updated_at
   FROM players
- WHERE id = $1
 `
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func (r *Repo) GetByID(ctx context.Context, id uuid.UUID) (*player.Player, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = selectPlayerColumns + ` WHERE id = $1`
+
+	p, err := scanPlayer(ex.QueryRowContext(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, player.ErrNotFound
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+func (r *Repo) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*player.Player, error) {
+	if len(ids) == 0 {
+		return []*player.Player{}, nil
+	}
+	ex := pickExecutor(ctx, r.db)
+
+	arr := make([]string, 0, len(ids))
+	for _, id := range ids {
+		arr = append(arr, id.String())
+	}
+
+	const q = selectPlayerColumns + ` WHERE id = ANY($1::uuid[])`
+
+	rows, err := ex.QueryContext(ctx, q, pq.Array(arr))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]*player.Player, 0, len(ids))
+	for rows.Next() {
+		p, err := scanPlayer(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func scanPlayer(row rowScanner) (*player.Player, error) {
 	var (
 		p                         player.Player
-		status, gender            int16
+		status, gender, kyc       int16
+		exclusion                 int16
+		excludedUntil             sql.NullTime
 		country, locale, tz       sql.NullString
 		phone, reason             sql.NullString
+		statusUntil               sql.NullTime
+		emailVerified             sql.NullTime
+		phoneVerified             sql.NullTime
 		first, last               sql.NullString
-		regIP                     sql.NullString
+		regIP, ipCountry          sql.NullString
 		birth                     sql.NullTime
 		registeredAt, lastLoginAt sql.NullTime
 		metadataRaw               []byte
@@ -45,24 +107,36 @@ SELECT id, email, phone, status, status_reason,
 		createdAt, updatedAt      time.Time
 	)
 
-	row := ex.QueryRowContext(ctx, q, id)
 	err := row.Scan(
-		&p.ID, &p.Email, &phone, &status, &reason,
+		&p.ID, &p.Email, &phone, &emailVerified, &phoneVerified,
+		&status, &reason, &statusUntil, &kyc,
+		&exclusion, &excludedUntil,
 		&country, &locale, &tz,
 		&first, &last, &birth, &gender,
-		&regIP, &registeredAt, &lastLoginAt,
+		&regIP, &ipCountry, &registeredAt, &lastLoginAt,
 		&metadataRaw, &version, &createdAt, &updatedAt,
 	)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, player.ErrNotFound
-		}
 		return nil, err
 	}
 
 	p.Phone = phone.String
+	if emailVerified.Valid {
+		p.EmailVerifiedAt = emailVerified.Time
+	}
+	if phoneVerified.Valid {
+		p.PhoneVerifiedAt = phoneVerified.Time
+	}
 	p.Status = player.Status(status)
 	p.StatusReason = reason.String
+	p.KYCLevel = player.KYCLevel(kyc)
+	p.ExclusionKind = player.ExclusionKind(exclusion)
+	if excludedUntil.Valid {
+		p.ExcludedUntil = excludedUntil.Time
+	}
+	if statusUntil.Valid {
+		p.StatusUntil = statusUntil.Time
+	}
 	p.Address = player.Address{CountryCode: country.String, Locale: locale.String, TimeZone: tz.String}
 	p.FirstName = first.String
 	p.LastName = last.String
@@ -70,6 +144,7 @@ SELECT id, email, phone, status, status_reason,
 		p.BirthDate = birth.Time
 	}
 	p.Gender = player.Gender(gender)
+	p.RegistrationIPCountry = ipCountry.String
 
 	if regIP.Valid && regIP.String != "" {
 		p.RegistrationIP = net.ParseIP(regIP.String)
@@ -93,19 +168,143 @@ SELECT id, email, phone, status, status_reason,
 	return &p, nil
 }
 
+// GetByEmail finds the player by any of its emails, not only the primary one.
 func (r *Repo) GetByEmail(ctx context.Context, email string) (*player.Player, error) {
+	id, err := playerIDByCredential(ctx, pickExecutor(ctx, r.db), player.CredentialEmail, email)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+// GetByPhone finds the player by any of its phones, not only the primary one.
+func (r *Repo) GetByPhone(ctx context.Context, phone string) (*player.Player, error) {
+	id, err := playerIDByCredential(ctx, pickExecutor(ctx, r.db), player.CredentialPhone, phone)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
+// sortColumns maps usecase sort fields to SQL; anything else is rejected.
+var sortColumns = map[string]string{
+	player.SortCreatedAt:    "created_at",
+	player.SortRegisteredAt: "registered_at",
+	player.SortLastLoginAt:  "last_login_at",
+	player.SortEmail:        "email",
+	player.SortStatus:       "status",
+	player.SortCountry:      "country_code",
+}
+
+func (r *Repo) List(ctx context.Context, f player.ListFilter) ([]*player.Player, int, error) {
 	ex := pickExecutor(ctx, r.db)
 
-	const q = `SELECT id FROM players WHERE email = $1`
-	var id uuid.UUID
-	err := ex.QueryRowContext(ctx, q, email).Scan(&id)
+	col, ok := sortColumns[f.SortBy]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: unsupported sort field %s", player.ErrValidation, f.SortBy)
+	}
+	dir := "ASC"
+	if f.Desc {
+		dir = "DESC"
+	}
+
+	var (
+		where []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if f.Search != "" {
+		p := arg("%" + escapeLike(f.Search) + "%")
+		where = append(where, "(email ILIKE "+p+
+			" OR phone ILIKE "+p+
+			" OR first_name ILIKE "+p+
+			" OR last_name ILIKE "+p+
+			" OR id::text ILIKE "+p+")")
+	}
+	if f.Country != "" {
+		where = append(where, "country_code = "+arg(f.Country))
+	}
+	if f.Currency != "" {
+		// players have no currency column yet, it comes in with registration metadata
+		where = append(where, "upper(metadata->>'currency') = "+arg(f.Currency))
+	}
+	if f.EmailVerified != nil {
+		where = append(where, "email_verified_at IS "+notNull(*f.EmailVerified))
+	}
+	if f.PhoneVerified != nil {
+		where = append(where, "phone_verified_at IS "+notNull(*f.PhoneVerified))
+	}
+	if f.KYCLevel != nil {
+		where = append(where, "kyc_level = "+arg(int16(*f.KYCLevel)))
+	}
+
+	cond := ""
+	if len(where) > 0 {
+		cond = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	var total int
+	if err := ex.QueryRowContext(ctx, `SELECT count(*) FROM players`+cond, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []*player.Player{}, 0, nil
+	}
+
+	q := selectPlayerColumns + cond +
+		" ORDER BY " + col + " " + dir + " NULLS LAST, id " + dir +
+		" LIMIT " + arg(f.Limit) + " OFFSET " + arg(f.Offset)
+
+	rows, err := ex.QueryContext(ctx, q, args...)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, player.ErrNotFound
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]*player.Player, 0, f.Limit)
+	for rows.Next() {
+		p, err := scanPlayer(rows)
+		if err != nil {
+			return nil, 0, err
 		}
+		items = append(items, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// ListStatusExpired returns ids of players whose temporary status ended at or before now.
+func (r *Repo) ListStatusExpired(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+SELECT id
+  FROM players
+ WHERE status_until IS NOT NULL AND status_until <= $1
+ ORDER BY status_until
+ LIMIT $2
+`
+	rows, err := ex.QueryContext(ctx, q, now, limit)
+	if err != nil {
 		return nil, err
 	}
-	return r.GetByID(ctx, id)
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
 }
 
 func (r *Repo) Create(ctx context.Context, p *player.Player) error {
@@ -115,27 +314,39 @@ func (r *Repo) Create(ctx context.Context, p *player.Player) error {
 
 	const q = `
 INSERT INTO players (
-  id, email, phone, status, status_reason,
+  id, email, phone, email_verified_at, phone_verified_at,
+  status, status_reason, status_until,
   country_code, locale, time_zone,
   first_name, last_name, birth_date, gender,
   registration_ip, registered_at, last_login_at,
-  metadata, version, created_at, updated_at
+  metadata, version, created_at, updated_at,
+  kyc_level, registration_ip_country,
+  exclusion_kind, excluded_until
 ) VALUES (
   $1,$2,$3,$4,$5,
   $6,$7,$8,
-  $9,$10,$11,$12,
-  $13,$14,$15,
-  $16,$17,$18,$19
+  $9,$10,$11,
+  $12,$13,$14,$15,
+  $16,$17,$18,
+  $19,$20,$21,$22,
+  $23,$24,
+  $25,$26
 )
 `
 	_, err := ex.ExecContext(ctx, q,
-		p.ID, p.Email, nullStr(p.Phone), int16(p.Status), nullStr(p.StatusReason),
+		p.ID, p.Email, nullStr(p.Phone), nullTime(p.EmailVerifiedAt), nullTime(p.PhoneVerifiedAt),
+		int16(p.Status), nullStr(p.StatusReason), nullTime(p.StatusUntil),
 		nullStr(p.Address.CountryCode), nullStr(p.Address.Locale), nullStr(p.Address.TimeZone),
 		nullStr(p.FirstName), nullStr(p.LastName), nullTime(p.BirthDate), int16(p.Gender),
 		nullIP(p.RegistrationIP), nullTime(p.RegisteredAt), nullTime(p.LastLoginAt),
 		meta, p.Version, p.CreatedAt, p.UpdatedAt,
+		int16(p.KYCLevel), nullStr(p.RegistrationIPCountry),
+		int16(p.ExclusionKind), nullTime(p.ExcludedUntil),
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	return r.syncCredentials(ctx, ex, p)
 }
 
 func (r *Repo) Update(ctx context.Context, p *player.Player) error {
@@ -143,26 +354,43 @@ func (r *Repo) Update(ctx context.Context, p *player.Player) error {
 
 	meta, _ := json.Marshal(p.Metadata)
 
-	// optimistic lock by version
+	// optimistic lock by version; the contact columns as they were before the
+	// update come back so the credentials are only touched when they changed
 	const q = `
 UPDATE players
    SET phone=$2,
        status=$3,
        status_reason=$4,
-       country_code=$5, locale=$6, time_zone=$7,
-       first_name=$8, last_name=$9, birth_date=$10, gender=$11,
-       registration_ip=$12,
-       registered_at=$13, last_login_at=$14,
-       metadata=$15,
-       version=$16,
-       updated_at=$17
- WHERE id=$1 AND version=$18
+       status_until=$5,
+       country_code=$6, locale=$7, time_zone=$8,
+       first_name=$9, last_name=$10, birth_date=$11, gender=$12,
+       registration_ip=$13,
+       registered_at=$14, last_login_at=$15,
+       metadata=$16,
+       version=$17,
+       updated_at=$18,
+       email_verified_at=$20, phone_verified_at=$21,
+       kyc_level=$22,
+       exclusion_kind=$23, excluded_until=$24
+  FROM (
+    SELECT phone, email_verified_at, phone_verified_at
+      FROM players
+     WHERE id=$1
+       FOR UPDATE
+  ) old
+ WHERE players.id=$1 AND players.version=$19
+RETURNING old.phone, old.email_verified_at, old.phone_verified_at
 `
-	res, err := ex.ExecContext(ctx, q,
+	var (
+		oldPhone                           sql.NullString
+		oldEmailVerified, oldPhoneVerified sql.NullTime
+	)
+	err := ex.QueryRowContext(ctx, q,
 		p.ID,
 		nullStr(p.Phone),
 		int16(p.Status),
 		nullStr(p.StatusReason),
+		nullTime(p.StatusUntil),
 		nullStr(p.Address.CountryCode), nullStr(p.Address.Locale), nullStr(p.Address.TimeZone),
 		nullStr(p.FirstName), nullStr(p.LastName), nullTime(p.BirthDate), int16(p.Gender),
 		nullIP(p.RegistrationIP),
@@ -171,17 +399,57 @@ UPDATE players
 		p.Version,
 		p.UpdatedAt,
 		p.Version-1,
-	)
+		nullTime(p.EmailVerifiedAt), nullTime(p.PhoneVerifiedAt),
+		int16(p.KYCLevel),
+		int16(p.ExclusionKind), nullTime(p.ExcludedUntil),
+	).Scan(&oldPhone, &oldEmailVerified, &oldPhoneVerified)
+	if errors.Is(err, sql.ErrNoRows) {
+		return player.ErrConflict
+	}
 	if err != nil {
 		return err
 	}
-	aff, _ := res.RowsAffected()
-	if aff == 0 {
-		return player.ErrConflict
+
+	// the email itself is never updated here, only its verification
+	if !sameTime(oldEmailVerified, p.EmailVerifiedAt) {
+		if err := syncPrimary(ctx, ex, p.ID, player.CredentialEmail, p.Email, p.EmailVerifiedAt, p.UpdatedAt); err != nil {
+			return err
+		}
+	}
+	if oldPhone.String != p.Phone || !sameTime(oldPhoneVerified, p.PhoneVerifiedAt) {
+		return syncPrimary(ctx, ex, p.ID, player.CredentialPhone, p.Phone, p.PhoneVerifiedAt, p.UpdatedAt)
 	}
 	return nil
 }
 
+// syncCredentials keeps the primary credentials in line with the player row.
+// Call it in the same transaction as the write to players.
+func (r *Repo) syncCredentials(ctx context.Context, ex executor, p *player.Player) error {
+	if err := syncPrimary(ctx, ex, p.ID, player.CredentialEmail, p.Email, p.EmailVerifiedAt, p.UpdatedAt); err != nil {
+		return err
+	}
+	return syncPrimary(ctx, ex, p.ID, player.CredentialPhone, p.Phone, p.PhoneVerifiedAt, p.UpdatedAt)
+}
+
+// sameTime reports whether a stored nullable timestamp is t, zero meaning NULL.
+func sameTime(stored sql.NullTime, t time.Time) bool {
+	if !stored.Valid {
+		return t.IsZero()
+	}
+	return stored.Time.Equal(t)
+}
+
+func notNull(b bool) string {
+	if b {
+		return "NOT NULL"
+	}
+	return "NULL"
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
 func nullStr(s string) sql.NullString {
 	if s == "" {
 		return sql.NullString{Valid: false}
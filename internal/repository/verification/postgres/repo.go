@@ -0,0 +1,150 @@
+package verificationpg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/player"
+	"players_service/internal/domain/verification"
+	"players_service/internal/infra/postgres"
+)
+
+type Repo struct {
+	db *sql.DB
+}
+
+func New(db *sql.DB) *Repo { return &Repo{db: db} }
+
+func (r *Repo) Create(ctx context.Context, c verification.Code) error {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+INSERT INTO verification_codes (
+  id, purpose, channel, destination, code_hash,
+  attempts, max_attempts, ip, created_at, expires_at, consumed_at
+) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,NULL)
+`
+	_, err := ex.ExecContext(ctx, q,
+		c.ID, string(c.Purpose), int16(c.Destination.Channel), c.Destination.Value, c.Hash,
+		c.Attempts, c.MaxAttempts, nullIP(c.IP), c.CreatedAt, c.ExpiresAt,
+	)
+	return err
+}
+
+func (r *Repo) Latest(ctx context.Context, purpose verification.Purpose, dst verification.Destination) (verification.Code, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+SELECT id, purpose, channel, destination, code_hash,
+       attempts, max_attempts, ip, created_at, expires_at
+  FROM verification_codes
+ WHERE purpose = $1 AND channel = $2 AND destination = $3 AND consumed_at IS NULL
+ ORDER BY created_at DESC
+ LIMIT 1
+`
+	var (
+		c       verification.Code
+		purp    string
+		channel int16
+		ip      sql.NullString
+	)
+	err := ex.QueryRowContext(ctx, q, string(purpose), int16(dst.Channel), dst.Value).Scan(
+		&c.ID, &purp, &channel, &c.Destination.Value, &c.Hash,
+		&c.Attempts, &c.MaxAttempts, &ip, &c.CreatedAt, &c.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return verification.Code{}, player.ErrNotFound
+		}
+		return verification.Code{}, err
+	}
+	c.Purpose = verification.Purpose(purp)
+	c.Destination.Channel = verification.Channel(channel)
+	if ip.Valid {
+		c.IP = net.ParseIP(ip.String)
+	}
+	return c, nil
+}
+
+// IncrementAttempt returns ErrTooManyAttempts when the code has no attempts
+// left or got consumed concurrently.
+func (r *Repo) IncrementAttempt(ctx context.Context, id uuid.UUID) (int, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+UPDATE verification_codes
+   SET attempts = attempts + 1
+ WHERE id = $1 AND consumed_at IS NULL AND attempts < max_attempts
+RETURNING attempts
+`
+	var n int
+	err := ex.QueryRowContext(ctx, q, id).Scan(&n)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, verification.ErrTooManyAttempts
+	}
+	return n, err
+}
+
+// Consume fails with ErrInvalidCode if the code got consumed concurrently.
+func (r *Repo) Consume(ctx context.Context, id uuid.UUID, now time.Time) error {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+UPDATE verification_codes
+   SET consumed_at = $2
+ WHERE id = $1 AND consumed_at IS NULL
+`
+	res, err := ex.ExecContext(ctx, q, id, now)
+	if err != nil {
+		return err
+	}
+	aff, _ := res.RowsAffected()
+	if aff == 0 {
+		return verification.ErrInvalidCode
+	}
+	return nil
+}
+
+// Lock takes a transaction-scoped advisory lock on key. Outside of a
+// transaction it would be released at once, so it refuses to run there.
+func (r *Repo) Lock(ctx context.Context, key string) error {
+	tx, ok := postgres.TxFromContext(ctx)
+	if !ok {
+		return errors.New("verification lock outside of a transaction")
+	}
+	_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtextextended($1, 0))`, key)
+	return err
+}
+
+func (r *Repo) CountByDestination(ctx context.Context, dst verification.Destination, since time.Time) (int, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `
+SELECT count(*) FROM verification_codes
+ WHERE channel = $1 AND destination = $2 AND created_at >= $3
+`
+	var n int
+	err := ex.QueryRowContext(ctx, q, int16(dst.Channel), dst.Value, since).Scan(&n)
+	return n, err
+}
+
+func (r *Repo) CountByIP(ctx context.Context, ip net.IP, since time.Time) (int, error) {
+	ex := pickExecutor(ctx, r.db)
+
+	const q = `SELECT count(*) FROM verification_codes WHERE ip = $1 AND created_at >= $2`
+	var n int
+	err := ex.QueryRowContext(ctx, q, ip.String(), since).Scan(&n)
+	return n, err
+}
+
+func nullIP(ip net.IP) any {
+	if len(ip) == 0 {
+		return nil
+	}
+	return ip.String()
+}
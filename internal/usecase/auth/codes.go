@@ -0,0 +1,159 @@
+package authuc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/player"
+	"players_service/internal/domain/verification"
+	verificationuc "players_service/internal/usecase/verification"
+)
+
+type SendCodeCmd struct {
+	Email string
+	Phone string
+	IP    string
+}
+
+// SendRegistrationCode sends a code to a contact that is not taken yet.
+// Register then expects this code.
+func (s *Service) SendRegistrationCode(ctx context.Context, cmd SendCodeCmd) (verificationuc.SendResult, error) {
+	dst, err := codeDestination(cmd.Email, cmd.Phone)
+	if err != nil {
+		return verificationuc.SendResult{}, err
+	}
+	if err := s.ensureContactFree(ctx, dst); err != nil {
+		return verificationuc.SendResult{}, err
+	}
+	return s.codes.Send(ctx, verification.PurposeRegistration, dst, parseIP(cmd.IP))
+}
+
+// SendVerifyCode sends a code to a contact of the player, to confirm it.
+func (s *Service) SendVerifyCode(ctx context.Context, playerID uuid.UUID, cmd SendCodeCmd) (verificationuc.SendResult, error) {
+	dst, err := codeDestination(cmd.Email, cmd.Phone)
+	if err != nil {
+		return verificationuc.SendResult{}, err
+	}
+	p, err := s.players.GetByID(ctx, playerID)
+	if err != nil {
+		return verificationuc.SendResult{}, err
+	}
+	if !ownsContact(p, dst) {
+		return verificationuc.SendResult{}, fmt.Errorf("%w: not a contact of the player", player.ErrForbidden)
+	}
+	if contactVerified(p, dst) {
+		return verificationuc.SendResult{}, fmt.Errorf("%w: %s already verified", player.ErrForbidden, dst.Channel)
+	}
+	return s.codes.Send(ctx, verification.PurposeVerify, dst, parseIP(cmd.IP))
+}
+
+type VerifyContactCmd struct {
+	Email string
+	Phone string
+	Code  string // sent by SendVerifyCode
+}
+
+// VerifyContact confirms the email or phone of the player with a code.
+func (s *Service) VerifyContact(ctx context.Context, playerID uuid.UUID, cmd VerifyContactCmd) (*player.Player, error) {
+	dst, err := codeDestination(cmd.Email, cmd.Phone)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.codes.Verify(ctx, verification.PurposeVerify, dst, cmd.Code); err != nil {
+		return nil, err
+	}
+
+	return s.markVerified(ctx, playerID, dst)
+}
+
+func (s *Service) markVerified(ctx context.Context, playerID uuid.UUID, dst verification.Destination) (*player.Player, error) {
+	if dst.Channel == verification.ChannelEmail {
+		return s.contacts.VerifyEmail(ctx, playerID, dst.Value)
+	}
+	return s.contacts.VerifyPhone(ctx, playerID, dst.Value)
+}
+
+// RequestCredential sends a code to an email or phone the player wants to add.
+// A value already used by any player yields ErrConflict.
+func (s *Service) RequestCredential(ctx context.Context, playerID uuid.UUID, cmd SendCodeCmd) (verificationuc.SendResult, error) {
+	dst, err := codeDestination(cmd.Email, cmd.Phone)
+	if err != nil {
+		return verificationuc.SendResult{}, err
+	}
+	if _, err := s.players.GetByID(ctx, playerID); err != nil {
+		return verificationuc.SendResult{}, err
+	}
+	if err := s.ensureContactFree(ctx, dst); err != nil {
+		return verificationuc.SendResult{}, err
+	}
+	return s.codes.Send(ctx, verification.PurposeCredential, dst, parseIP(cmd.IP))
+}
+
+// ConfirmCredential adds the email or phone once the code sent by
+// RequestCredential is confirmed.
+func (s *Service) ConfirmCredential(ctx context.Context, playerID uuid.UUID, cmd VerifyContactCmd) (player.Credential, error) {
+	dst, err := codeDestination(cmd.Email, cmd.Phone)
+	if err != nil {
+		return player.Credential{}, err
+	}
+	if err := s.codes.Verify(ctx, verification.PurposeCredential, dst, cmd.Code); err != nil {
+		return player.Credential{}, err
+	}
+
+	kind := player.CredentialEmail
+	if dst.Channel == verification.ChannelPhone {
+		kind = player.CredentialPhone
+	}
+	return s.contacts.AddCredential(ctx, playerID, kind, dst.Value)
+}
+
+// codeDestination picks where a code goes: the email if given, else the phone.
+func codeDestination(email, phone string) (verification.Destination, error) {
+	if strings.TrimSpace(email) != "" {
+		return verification.EmailDestination(email)
+	}
+	if strings.TrimSpace(phone) != "" {
+		return verification.PhoneDestination(phone)
+	}
+	return verification.Destination{}, fmt.Errorf("%w: email or phone required", verification.ErrInvalidDestination)
+}
+
+func (s *Service) ensureContactFree(ctx context.Context, dst verification.Destination) error {
+	var err error
+	switch dst.Channel {
+	case verification.ChannelEmail:
+		_, err = s.players.GetByEmail(ctx, dst.Value)
+	case verification.ChannelPhone:
+		_, err = s.players.GetByPhone(ctx, dst.Value)
+	}
+	switch {
+	case err == nil:
+		return fmt.Errorf("%w: %s already registered", player.ErrConflict, dst.Channel)
+	case errors.Is(err, player.ErrNotFound):
+		return nil
+	default:
+		return err
+	}
+}
+
+func contactVerified(p *player.Player, dst verification.Destination) bool {
+	if dst.Channel == verification.ChannelEmail {
+		return !p.EmailVerifiedAt.IsZero()
+	}
+	return !p.PhoneVerifiedAt.IsZero()
+}
+
+func ownsContact(p *player.Player, dst verification.Destination) bool {
+	switch dst.Channel {
+	case verification.ChannelEmail:
+		return strings.EqualFold(p.Email, dst.Value)
+	case verification.ChannelPhone:
+		return p.Phone != "" && p.Phone == dst.Value
+	default:
+		return false
+	}
+}
@@ -0,0 +1,700 @@
+package authuc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/auth"
+	"players_service/internal/domain/player"
+	"players_service/internal/domain/verification"
+	playeruc "players_service/internal/usecase/player"
+	verificationuc "players_service/internal/usecase/verification"
+)
+
+// In-memory fakes of the ports, enough to run the usecases without a database.
+
+// fakeTx holds the row locks taken inside one fakeUoW transaction.
+type fakeTx struct {
+	held []*sync.Mutex
+}
+
+type txKey struct{}
+
+type fakeUoW struct{}
+
+func (fakeUoW) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*fakeTx); ok {
+		return fn(ctx)
+	}
+	tx := &fakeTx{}
+	defer func() {
+		for _, m := range tx.held {
+			m.Unlock()
+		}
+	}()
+	return fn(context.WithValue(ctx, txKey{}, tx))
+}
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+type fakePlayers struct {
+	mu      sync.Mutex
+	players map[uuid.UUID]player.Player
+}
+
+func (r *fakePlayers) add(p *player.Player) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.players == nil {
+		r.players = map[uuid.UUID]player.Player{}
+	}
+	r.players[p.ID] = *p
+}
+
+func (r *fakePlayers) GetByID(_ context.Context, id uuid.UUID) (*player.Player, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.players[id]
+	if !ok {
+		return nil, player.ErrNotFound
+	}
+	return &p, nil
+}
+
+func (r *fakePlayers) GetByEmail(_ context.Context, email string) (*player.Player, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.players {
+		if p.Email == email {
+			return &p, nil
+		}
+	}
+	return nil, player.ErrNotFound
+}
+
+func (r *fakePlayers) GetByPhone(_ context.Context, phone string) (*player.Player, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.players {
+		if p.Phone != "" && p.Phone == phone {
+			return &p, nil
+		}
+	}
+	return nil, player.ErrNotFound
+}
+
+func (r *fakePlayers) Update(_ context.Context, p *player.Player) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.players[p.ID] = *p
+	return nil
+}
+
+type fakePasswords struct {
+	mu    sync.Mutex
+	creds map[uuid.UUID]auth.PasswordCredential
+}
+
+func (r *fakePasswords) Get(_ context.Context, playerID uuid.UUID) (auth.PasswordCredential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.creds[playerID]
+	if !ok {
+		return auth.PasswordCredential{}, player.ErrNotFound
+	}
+	return c, nil
+}
+
+func (r *fakePasswords) Upsert(_ context.Context, c auth.PasswordCredential) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.creds == nil {
+		r.creds = map[uuid.UUID]auth.PasswordCredential{}
+	}
+	r.creds[c.PlayerID] = c
+	return nil
+}
+
+type fakeSessions struct {
+	mu       sync.Mutex
+	sessions map[uuid.UUID]auth.Session
+}
+
+func (r *fakeSessions) Create(_ context.Context, s auth.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sessions == nil {
+		r.sessions = map[uuid.UUID]auth.Session{}
+	}
+	r.sessions[s.ID] = s
+	return nil
+}
+
+func (r *fakeSessions) GetByID(_ context.Context, id uuid.UUID) (auth.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[id]
+	if !ok {
+		return auth.Session{}, player.ErrNotFound
+	}
+	return s, nil
+}
+
+func (r *fakeSessions) GetByRefreshHash(_ context.Context, hash string) (auth.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.sessions {
+		if s.RefreshHash == hash {
+			return s, nil
+		}
+	}
+	return auth.Session{}, player.ErrNotFound
+}
+
+func (r *fakeSessions) revokeWhere(at time.Time, reason string, match func(auth.Session) bool) int {
+	n := 0
+	for id, s := range r.sessions {
+		if s.RevokedAt.IsZero() && match(s) {
+			s.RevokedAt = at
+			s.RevokeReason = reason
+			r.sessions[id] = s
+			n++
+		}
+	}
+	return n
+}
+
+func (r *fakeSessions) Revoke(_ context.Context, id uuid.UUID, at time.Time, reason string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.revokeWhere(at, reason, func(s auth.Session) bool { return s.ID == id }) == 1, nil
+}
+
+func (r *fakeSessions) RevokeFamily(_ context.Context, familyID uuid.UUID, at time.Time, reason string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.revokeWhere(at, reason, func(s auth.Session) bool { return s.FamilyID == familyID }), nil
+}
+
+func (r *fakeSessions) RevokeAllForPlayer(_ context.Context, playerID uuid.UUID, at time.Time, reason string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.revokeWhere(at, reason, func(s auth.Session) bool { return s.PlayerID == playerID }), nil
+}
+
+func (r *fakeSessions) RevokeOthers(_ context.Context, playerID, keepID uuid.UUID, at time.Time, reason string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.revokeWhere(at, reason, func(s auth.Session) bool { return s.PlayerID == playerID && s.ID != keepID }), nil
+}
+
+func (r *fakeSessions) active(now time.Time) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, s := range r.sessions {
+		if s.Active(now) {
+			n++
+		}
+	}
+	return n
+}
+
+type fakeSocial struct {
+	mu       sync.Mutex
+	accounts []auth.SocialAccount
+	locks    sync.Map // player id -> *sync.Mutex
+	// deleteDelay widens the window between reading and deleting, for races
+	deleteDelay time.Duration
+}
+
+func (r *fakeSocial) Get(_ context.Context, provider auth.Provider, subject string) (auth.SocialAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, a := range r.accounts {
+		if a.Provider == provider && a.Subject == subject {
+			return a, nil
+		}
+	}
+	return auth.SocialAccount{}, player.ErrNotFound
+}
+
+func (r *fakeSocial) ListByPlayer(_ context.Context, playerID uuid.UUID) ([]auth.SocialAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []auth.SocialAccount
+	for _, a := range r.accounts {
+		if a.PlayerID == playerID {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+// LockByPlayer holds a per-player lock until the fakeUoW transaction ends.
+func (r *fakeSocial) LockByPlayer(ctx context.Context, playerID uuid.UUID) ([]auth.SocialAccount, error) {
+	tx, ok := ctx.Value(txKey{}).(*fakeTx)
+	if !ok {
+		return nil, errors.New("lock outside of a transaction")
+	}
+	m, _ := r.locks.LoadOrStore(playerID, &sync.Mutex{})
+	m.(*sync.Mutex).Lock()
+	tx.held = append(tx.held, m.(*sync.Mutex))
+	return r.ListByPlayer(ctx, playerID)
+}
+
+func (r *fakeSocial) Create(_ context.Context, a auth.SocialAccount) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, x := range r.accounts {
+		if (x.Provider == a.Provider && x.Subject == a.Subject) || (x.PlayerID == a.PlayerID && x.Provider == a.Provider) {
+			return player.ErrConflict
+		}
+	}
+	r.accounts = append(r.accounts, a)
+	return nil
+}
+
+func (r *fakeSocial) Delete(_ context.Context, playerID uuid.UUID, provider auth.Provider) error {
+	time.Sleep(r.deleteDelay)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, a := range r.accounts {
+		if a.PlayerID == playerID && a.Provider == provider {
+			r.accounts = append(r.accounts[:i], r.accounts[i+1:]...)
+			return nil
+		}
+	}
+	return player.ErrNotFound
+}
+
+// fakeVerifier takes the ID token for the subject itself.
+type fakeVerifier struct{}
+
+func (fakeVerifier) Verify(_ context.Context, idToken string) (auth.ExternalIdentity, error) {
+	if idToken == "" {
+		return auth.ExternalIdentity{}, auth.ErrInvalidToken
+	}
+	return auth.ExternalIdentity{Subject: idToken}, nil
+}
+
+type fakeFailures struct {
+	mu       sync.Mutex
+	failures []auth.LoginFailure
+}
+
+func (r *fakeFailures) Record(_ context.Context, f auth.LoginFailure) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failures = append(r.failures, f)
+	return nil
+}
+
+func (r *fakeFailures) CountForPlayer(_ context.Context, playerID uuid.UUID, since time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, f := range r.failures {
+		if f.PlayerID == playerID && !f.At.Before(since) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *fakeFailures) CountForIP(_ context.Context, ip net.IP, since time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, f := range r.failures {
+		if f.IP.Equal(ip) && !f.At.Before(since) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *fakeFailures) ClearForPlayer(_ context.Context, playerID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := r.failures[:0]
+	for _, f := range r.failures {
+		if f.PlayerID != playerID {
+			kept = append(kept, f)
+		}
+	}
+	r.failures = kept
+	return nil
+}
+
+type fakeLogins struct {
+	mu      sync.Mutex
+	records []auth.LoginRecord
+}
+
+func (r *fakeLogins) Append(_ context.Context, l auth.LoginRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, l)
+	return nil
+}
+
+func (r *fakeLogins) List(context.Context, auth.LoginFilter) ([]auth.LoginRecord, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeLogins) SharedIPs(context.Context, uuid.UUID, int) ([]auth.SharedIP, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeLogins) outcomes() []auth.LoginOutcome {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]auth.LoginOutcome, len(r.records))
+	for i, l := range r.records {
+		out[i] = l.Outcome
+	}
+	return out
+}
+
+// fakeHasher "hashes" by prefixing and counts Verify calls.
+type fakeHasher struct {
+	mu       sync.Mutex
+	verifies int
+}
+
+func (h *fakeHasher) Hash(pw string) (string, error) { return "hash:" + pw, nil }
+
+func (h *fakeHasher) Verify(pw, hash string) (bool, error) {
+	h.mu.Lock()
+	h.verifies++
+	h.mu.Unlock()
+	return hash == "hash:"+pw, nil
+}
+
+type fakeAccessTokens struct {
+	mu     sync.Mutex
+	claims map[string]auth.AccessClaims
+}
+
+func (t *fakeAccessTokens) Issue(c auth.AccessClaims) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.claims == nil {
+		t.claims = map[string]auth.AccessClaims{}
+	}
+	tok := uuid.NewString()
+	t.claims[tok] = c
+	return tok, nil
+}
+
+func (t *fakeAccessTokens) Parse(tok string) (auth.AccessClaims, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c, ok := t.claims[tok]
+	if !ok {
+		return auth.AccessClaims{}, auth.ErrInvalidToken
+	}
+	return c, nil
+}
+
+type fakeIntTokens struct {
+	mu     sync.Mutex
+	claims map[string]auth.IntegrationClaims
+}
+
+func (t *fakeIntTokens) Issue(c auth.IntegrationClaims) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.claims == nil {
+		t.claims = map[string]auth.IntegrationClaims{}
+	}
+	tok := uuid.NewString()
+	t.claims[tok] = c
+	return tok, nil
+}
+
+func (t *fakeIntTokens) Parse(tok string) (auth.IntegrationClaims, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c, ok := t.claims[tok]
+	if !ok {
+		return auth.IntegrationClaims{}, auth.ErrInvalidToken
+	}
+	return c, nil
+}
+
+type fakeAudit struct {
+	mu      sync.Mutex
+	entries []auth.PasswordAudit
+}
+
+func (r *fakeAudit) Append(_ context.Context, a auth.PasswordAudit) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, a)
+	return nil
+}
+
+// fakeCodes allows maxPerDest codes per destination and accepts "000000",
+// once per destination.
+type fakeCodes struct {
+	mu         sync.Mutex
+	maxPerDest int
+	issued     map[verification.Destination]int
+	delivered  []verification.Destination
+	ids        map[verification.Destination]uuid.UUID
+	consumed   map[uuid.UUID]bool
+}
+
+func (c *fakeCodes) issue(dst verification.Destination, deliver bool) (verificationuc.SendResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.issued == nil {
+		c.issued = map[verification.Destination]int{}
+	}
+	if c.maxPerDest > 0 && c.issued[dst] >= c.maxPerDest {
+		return verificationuc.SendResult{}, verification.ErrTooManyRequests
+	}
+	c.issued[dst]++
+	if deliver {
+		c.delivered = append(c.delivered, dst)
+	}
+	return verificationuc.SendResult{CodeLength: 6}, nil
+}
+
+func (c *fakeCodes) Send(_ context.Context, _ verification.Purpose, dst verification.Destination, _ net.IP) (verificationuc.SendResult, error) {
+	return c.issue(dst, true)
+}
+
+func (c *fakeCodes) Pretend(_ context.Context, _ verification.Purpose, dst verification.Destination, _ net.IP) (verificationuc.SendResult, error) {
+	return c.issue(dst, false)
+}
+
+func (c *fakeCodes) Verify(ctx context.Context, purpose verification.Purpose, dst verification.Destination, value string) error {
+	id, err := c.Check(ctx, purpose, dst, value)
+	if err != nil {
+		return err
+	}
+	return c.Consume(ctx, id)
+}
+
+func (c *fakeCodes) Check(_ context.Context, _ verification.Purpose, dst verification.Destination, value string) (uuid.UUID, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if value != "000000" {
+		return uuid.Nil, verification.ErrInvalidCode
+	}
+	if c.ids == nil {
+		c.ids = map[verification.Destination]uuid.UUID{}
+	}
+	id, ok := c.ids[dst]
+	if !ok {
+		id = uuid.New()
+		c.ids[dst] = id
+	}
+	if c.consumed[id] {
+		return uuid.Nil, verification.ErrInvalidCode
+	}
+	return id, nil
+}
+
+func (c *fakeCodes) Consume(_ context.Context, id uuid.UUID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.consumed == nil {
+		c.consumed = map[uuid.UUID]bool{}
+	}
+	if c.consumed[id] {
+		return verification.ErrInvalidCode
+	}
+	c.consumed[id] = true
+	return nil
+}
+
+func (c *fakeCodes) used(dst verification.Destination) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id, ok := c.ids[dst]
+	return ok && c.consumed[id]
+}
+
+// fakeRegistrar stands in for playeruc: duplicate emails are a conflict.
+type fakeRegistrar struct {
+	players *fakePlayers
+	clock   *fakeClock
+}
+
+func (r *fakeRegistrar) CreatePlayer(ctx context.Context, cmd playeruc.CreatePlayerCmd) (*player.Player, error) {
+	email := strings.ToLower(strings.TrimSpace(cmd.Email))
+	if _, err := r.players.GetByEmail(ctx, email); err == nil {
+		return nil, player.ErrConflict
+	}
+	p := &player.Player{ID: uuid.New(), Email: email, Status: player.StatusActive, Metadata: cmd.Metadata, Version: 1}
+	r.players.add(p)
+	return p, nil
+}
+
+func (r *fakeRegistrar) VerifyEmail(ctx context.Context, playerID uuid.UUID, _ string) (*player.Player, error) {
+	p, err := r.players.GetByID(ctx, playerID)
+	if err != nil {
+		return nil, err
+	}
+	p.EmailVerifiedAt = r.clock.Now()
+	r.players.add(p)
+	return p, nil
+}
+
+func (r *fakeRegistrar) VerifyPhone(context.Context, uuid.UUID, string) (*player.Player, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeRegistrar) AddCredential(context.Context, uuid.UUID, player.CredentialKind, string) (player.Credential, error) {
+	return player.Credential{}, errors.New("not implemented")
+}
+
+// playerStore hands fakePlayers to playeruc, which only reads and updates
+// players for status changes.
+type playerStore struct {
+	playeruc.PlayerRepository
+	players *fakePlayers
+}
+
+func (s playerStore) GetByID(ctx context.Context, id uuid.UUID) (*player.Player, error) {
+	return s.players.GetByID(ctx, id)
+}
+
+func (s playerStore) Update(ctx context.Context, p *player.Player) error {
+	return s.players.Update(ctx, p)
+}
+
+type fakeStatusEvents struct {
+	mu     sync.Mutex
+	events []player.PlayerStatusEvent
+}
+
+func (r *fakeStatusEvents) Append(_ context.Context, ev player.PlayerStatusEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, ev)
+	return nil
+}
+
+func (r *fakeStatusEvents) List(context.Context, player.StatusEventFilter) ([]player.PlayerStatusEvent, error) {
+	return nil, errors.New("not implemented")
+}
+
+type fakeOutbox struct {
+	mu       sync.Mutex
+	messages []playeruc.OutboxMessage
+}
+
+func (r *fakeOutbox) Enqueue(_ context.Context, msg playeruc.OutboxMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, msg)
+	return nil
+}
+
+type testEnv struct {
+	svc       *Service
+	clock     *fakeClock
+	players   *fakePlayers
+	passwords *fakePasswords
+	sessions  *fakeSessions
+	social    *fakeSocial
+	failures  *fakeFailures
+	logins    *fakeLogins
+	hasher    *fakeHasher
+	audit     *fakeAudit
+	codes     *fakeCodes
+	statuses  *playeruc.Service
+	events    *fakeStatusEvents
+	outbox    *fakeOutbox
+}
+
+func newTestEnv(t *testing.T, cfg Config) *testEnv {
+	t.Helper()
+	e := &testEnv{
+		clock:     &fakeClock{now: time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)},
+		players:   &fakePlayers{},
+		passwords: &fakePasswords{},
+		sessions:  &fakeSessions{},
+		social:    &fakeSocial{},
+		failures:  &fakeFailures{},
+		logins:    &fakeLogins{},
+		hasher:    &fakeHasher{},
+		audit:     &fakeAudit{},
+		codes:     &fakeCodes{},
+		events:    &fakeStatusEvents{},
+		outbox:    &fakeOutbox{},
+	}
+	e.statuses = playeruc.New(fakeUoW{}, playerStore{players: e.players}, e.events, nil, nil, e.outbox, e.clock)
+	registrar := &fakeRegistrar{players: e.players, clock: e.clock}
+	svc, err := New(Deps{
+		UoW:       fakeUoW{},
+		Players:   e.players,
+		Registrar: registrar,
+		Contacts:  registrar,
+		Statuses:  e.statuses,
+		Passwords: e.passwords,
+		Sessions:  e.sessions,
+		Social:    e.social,
+		Failures:  e.failures,
+		Logins:    e.logins,
+		Hasher:    e.hasher,
+		Audit:     e.audit,
+		Tokens:    &fakeAccessTokens{},
+		IntTokens: &fakeIntTokens{},
+		Codes:     e.codes,
+		Clock:     e.clock,
+		Verifiers: map[auth.Provider]ProviderVerifier{
+			auth.ProviderGoogle:   fakeVerifier{},
+			auth.ProviderTelegram: fakeVerifier{},
+		},
+	}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.svc = svc
+	return e
+}
+
+// addPlayer stores an active player with the given password ("" for none).
+func (e *testEnv) addPlayer(t *testing.T, email, password string) *player.Player {
+	t.Helper()
+	p := &player.Player{
+		ID:      uuid.New(),
+		Email:   strings.ToLower(email),
+		Status:  player.StatusActive,
+		Version: 1,
+	}
+	e.players.add(p)
+	if password != "" {
+		hash, _ := e.hasher.Hash(password)
+		_ = e.passwords.Upsert(context.Background(), auth.NewPasswordCredential(p.ID, hash, e.clock.Now()))
+	}
+	return p
+}
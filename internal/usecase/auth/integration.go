@@ -0,0 +1,93 @@
+package authuc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/auth"
+	"players_service/internal/domain/player"
+)
+
+type IntegrationToken struct {
+	Token  string
+	Claims auth.IntegrationClaims
+}
+
+// IssueIntegrationToken mints a short-lived token that identifies the player
+// to the game provider, in the player's currency.
+func (s *Service) IssueIntegrationToken(ctx context.Context, playerID uuid.UUID, provider string) (IntegrationToken, error) {
+	prov, err := auth.NormalizeIntegrationProvider(provider)
+	if err != nil {
+		return IntegrationToken{}, err
+	}
+
+	p, err := s.players.GetByID(ctx, playerID)
+	if err != nil {
+		return IntegrationToken{}, err
+	}
+	if !p.CanLogin() {
+		return IntegrationToken{}, auth.ErrPlayerInactive
+	}
+	currency := playerCurrency(p)
+	if currency == "" {
+		return IntegrationToken{}, fmt.Errorf("%w: player has no currency", player.ErrValidation)
+	}
+
+	now := s.clock.Now()
+	c := auth.IntegrationClaims{
+		PlayerID:  p.ID,
+		Currency:  currency,
+		Provider:  prov,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(s.cfg.IntegrationTTL),
+	}
+	tok, err := s.intTokens.Issue(c)
+	if err != nil {
+		return IntegrationToken{}, err
+	}
+	return IntegrationToken{Token: tok, Claims: c}, nil
+}
+
+type IntegrationIdentity struct {
+	Claims auth.IntegrationClaims
+	Player *player.Player
+}
+
+// VerifyIntegrationToken is called by the game provider. The token must be
+// valid, minted for that provider, and the player still active and playing
+// in the currency of the token.
+func (s *Service) VerifyIntegrationToken(ctx context.Context, provider, tok string) (IntegrationIdentity, error) {
+	prov, err := auth.NormalizeIntegrationProvider(provider)
+	if err != nil {
+		return IntegrationIdentity{}, err
+	}
+
+	c, err := s.intTokens.Parse(tok)
+	if err != nil {
+		return IntegrationIdentity{}, err
+	}
+	if c.Provider != prov || !s.clock.Now().Before(c.ExpiresAt) {
+		return IntegrationIdentity{}, auth.ErrInvalidToken
+	}
+
+	p, err := s.players.GetByID(ctx, c.PlayerID)
+	if err != nil {
+		return IntegrationIdentity{}, err
+	}
+	if !p.CanLogin() {
+		return IntegrationIdentity{}, auth.ErrPlayerInactive
+	}
+	if playerCurrency(p) != c.Currency {
+		return IntegrationIdentity{}, auth.ErrInvalidToken
+	}
+	return IntegrationIdentity{Claims: c, Player: p}, nil
+}
+
+// playerCurrency reads the currency from metadata, where registration puts it.
+func playerCurrency(p *player.Player) string {
+	c, _ := p.Metadata["currency"].(string)
+	return strings.ToUpper(c)
+}
@@ -0,0 +1,35 @@
+package authuc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"players_service/internal/domain/auth"
+)
+
+func TestVerifyIntegrationTokenOnlyForItsProvider(t *testing.T) {
+	e := newTestEnv(t, Config{IntegrationTTL: 5 * time.Minute})
+	ctx := context.Background()
+	p := e.addPlayer(t, "p@example.com", "")
+	p.Metadata = map[string]any{"currency": "EUR"}
+	e.players.add(p)
+
+	tok, err := e.svc.IssueIntegrationToken(ctx, p.ID, "evolution")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.svc.VerifyIntegrationToken(ctx, "pragmatic", tok.Token); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Fatalf("other provider: want ErrInvalidToken, got %v", err)
+	}
+	id, err := e.svc.VerifyIntegrationToken(ctx, "evolution", tok.Token)
+	if err != nil || id.Player.ID != p.ID || id.Claims.Currency != "EUR" {
+		t.Fatalf("own provider: %+v, %v", id, err)
+	}
+
+	e.clock.Advance(5 * time.Minute)
+	if _, err := e.svc.VerifyIntegrationToken(ctx, "evolution", tok.Token); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Fatalf("expired: want ErrInvalidToken, got %v", err)
+	}
+}
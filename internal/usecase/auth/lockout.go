@@ -0,0 +1,108 @@
+package authuc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/auth"
+	"players_service/internal/domain/player"
+	playeruc "players_service/internal/usecase/player"
+)
+
+// checkIPFailures refuses logins from an IP with too many recent failures.
+func (s *Service) checkIPFailures(ctx context.Context, ip net.IP) error {
+	if len(ip) == 0 {
+		return nil
+	}
+	n, err := s.failures.CountForIP(ctx, ip, s.clock.Now().Add(-s.cfg.Lockout.Window))
+	if err != nil {
+		return err
+	}
+	if n >= s.cfg.Lockout.MaxPerIP {
+		return auth.ErrLoginThrottled
+	}
+	return nil
+}
+
+// checkPlayerFailures refuses logins of a player with too many recent
+// failures, until they leave the window. It runs before the password is
+// checked, so the right password gets the same answer as a wrong one. The
+// freeze is what stops the attack, this only keeps the hasher out of it.
+func (s *Service) checkPlayerFailures(ctx context.Context, playerID uuid.UUID) error {
+	n, err := s.failures.CountForPlayer(ctx, playerID, s.clock.Now().Add(-s.cfg.Lockout.Window))
+	if err != nil {
+		return err
+	}
+	if n >= s.cfg.Lockout.MaxPerPlayer {
+		return auth.ErrLoginThrottled
+	}
+	return nil
+}
+
+// loginFailed records a failed password check, p is nil for an unknown login.
+// When the failures of the player or of the IP reach their threshold, the
+// player is frozen in the same transaction.
+func (s *Service) loginFailed(ctx context.Context, p *player.Player, ip net.IP) error {
+	return s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		now := s.clock.Now()
+
+		f := auth.LoginFailure{IP: ip, At: now}
+		if p != nil {
+			f.PlayerID = p.ID
+		}
+		if err := s.failures.Record(ctx, f); err != nil {
+			return err
+		}
+		if p == nil || p.Status != player.StatusActive {
+			return nil
+		}
+
+		since := now.Add(-s.cfg.Lockout.Window)
+		n, err := s.failures.CountForPlayer(ctx, p.ID, since)
+		if err != nil {
+			return err
+		}
+		over := n >= s.cfg.Lockout.MaxPerPlayer
+		if !over && len(ip) > 0 {
+			n, err := s.failures.CountForIP(ctx, ip, since)
+			if err != nil {
+				return err
+			}
+			over = n >= s.cfg.Lockout.MaxPerIP
+		}
+		if !over {
+			return nil
+		}
+		return s.freeze(ctx, p.ID, now)
+	})
+}
+
+// freeze moves the player to frozen through the player usecase, which writes
+// the status event and the outbox message.
+func (s *Service) freeze(ctx context.Context, playerID uuid.UUID, now time.Time) error {
+	cmd := playeruc.ChangeStatusCmd{
+		PlayerID: playerID,
+		ToStatus: player.StatusFrozen.String(),
+		Reason:   auth.LockoutReason,
+		Actor:    player.ActorSystem,
+	}
+	if s.cfg.Lockout.UnlockAfter > 0 {
+		cmd.Until = now.Add(s.cfg.Lockout.UnlockAfter)
+	}
+	_, _, err := s.statuses.ChangeStatus(ctx, cmd)
+	switch {
+	case err == nil:
+		log.Printf("auth: player %s frozen after failed logins", playerID)
+		return nil
+	case errors.Is(err, player.ErrConflict), errors.Is(err, player.ErrForbidden), errors.Is(err, player.ErrValidation):
+		// a concurrent attempt froze it first, or the status moved on
+		return nil
+	default:
+		return err
+	}
+}
@@ -0,0 +1,168 @@
+package authuc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"players_service/internal/domain/auth"
+	"players_service/internal/domain/player"
+	playeruc "players_service/internal/usecase/player"
+)
+
+func TestLoginFreezesPlayer(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		unlockAfter time.Duration
+	}{
+		{"temporary", 30 * time.Minute},
+		{"until an admin unfreezes", 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEnv(t, Config{Lockout: LockoutConfig{Window: 15 * time.Minute, MaxPerPlayer: 3, MaxPerIP: 100, UnlockAfter: tt.unlockAfter}})
+			ctx := context.Background()
+			p := e.addPlayer(t, "p@example.com", "right-password")
+
+			for i := 0; i < 3; i++ {
+				_, err := e.svc.Login(ctx, LoginCmd{Login: p.Email, Password: "wrong-password", IP: "203.0.113.7"})
+				if !errors.Is(err, auth.ErrInvalidCredentials) {
+					t.Fatalf("attempt %d: want ErrInvalidCredentials, got %v", i+1, err)
+				}
+			}
+
+			var until time.Time
+			if tt.unlockAfter > 0 {
+				until = e.clock.Now().Add(tt.unlockAfter)
+			}
+			got, _ := e.players.GetByID(ctx, p.ID)
+			if got.Status != player.StatusFrozen || got.StatusReason != auth.LockoutReason || !got.StatusUntil.Equal(until) {
+				t.Fatalf("status = %s (%q) until %v, want frozen until %v", got.Status.String(), got.StatusReason, got.StatusUntil, until)
+			}
+
+			if len(e.events.events) != 1 {
+				t.Fatalf("%d status events, want 1", len(e.events.events))
+			}
+			ev := e.events.events[0]
+			if ev.PlayerID != p.ID || ev.From != player.StatusActive || ev.To != player.StatusFrozen ||
+				ev.ActorType != player.ActorSystem || ev.Reason != auth.LockoutReason || !ev.Until.Equal(until) {
+				t.Fatalf("status event %+v", ev)
+			}
+
+			if len(e.outbox.messages) != 1 {
+				t.Fatalf("%d outbox messages, want 1", len(e.outbox.messages))
+			}
+			msg := e.outbox.messages[0]
+			var payload struct {
+				ID       string `json:"id"`
+				ToStatus string `json:"to_status"`
+			}
+			if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+				t.Fatal(err)
+			}
+			if msg.Type != "player.status.changed" || msg.AggregateID != p.ID || payload.ID != ev.ID.String() || payload.ToStatus != "frozen" {
+				t.Fatalf("outbox message %s %s %s", msg.Type, msg.AggregateID, msg.Payload)
+			}
+		})
+	}
+}
+
+func TestLoginThrottlesPlayerBeforePasswordCheck(t *testing.T) {
+	e := newTestEnv(t, Config{Lockout: LockoutConfig{Window: 15 * time.Minute, MaxPerPlayer: 3, MaxPerIP: 100, UnlockAfter: 10 * time.Minute}})
+	ctx := context.Background()
+	p := e.addPlayer(t, "p@example.com", "right-password")
+
+	for i := 0; i < 3; i++ {
+		_, err := e.svc.Login(ctx, LoginCmd{Login: p.Email, Password: "wrong-password", IP: "203.0.113.7"})
+		if !errors.Is(err, auth.ErrInvalidCredentials) {
+			t.Fatalf("attempt %d: want ErrInvalidCredentials, got %v", i+1, err)
+		}
+	}
+
+	verifies := e.hasher.verifies
+	for _, pw := range []string{"right-password", "wrong-password"} {
+		_, err := e.svc.Login(ctx, LoginCmd{Login: p.Email, Password: pw, IP: "198.51.100.4"})
+		if !errors.Is(err, auth.ErrLoginThrottled) {
+			t.Fatalf("%s while throttled: want ErrLoginThrottled, got %v", pw, err)
+		}
+	}
+	if e.hasher.verifies != verifies {
+		t.Fatal("password checked while throttled")
+	}
+
+	// the freeze runs out first, the expiry job reverts it
+	e.clock.Advance(15*time.Minute + time.Second)
+	if _, _, err := e.statuses.ChangeStatus(ctx, playeruc.ChangeStatusCmd{
+		PlayerID: p.ID, ToStatus: "active", Reason: "expired", Actor: player.ActorSystem,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.svc.Login(ctx, LoginCmd{Login: p.Email, Password: "right-password"}); err != nil {
+		t.Fatalf("after the window: %v", err)
+	}
+	if n, _ := e.failures.CountForPlayer(ctx, p.ID, time.Time{}); n != 0 {
+		t.Fatalf("%d failures left after a successful login", n)
+	}
+}
+
+func TestLoginThrottlesIP(t *testing.T) {
+	e := newTestEnv(t, Config{Lockout: LockoutConfig{Window: time.Hour, MaxPerPlayer: 100, MaxPerIP: 2}})
+	ctx := context.Background()
+	p := e.addPlayer(t, "p@example.com", "right-password")
+	other := e.addPlayer(t, "other@example.com", "other-password")
+
+	for _, login := range []string{"nobody@example.com", "p@example.com"} {
+		_, _ = e.svc.Login(ctx, LoginCmd{Login: login, Password: "guess-guess", IP: "203.0.113.7"})
+	}
+	if got, _ := e.players.GetByID(ctx, p.ID); got.Status != player.StatusFrozen {
+		t.Fatalf("status = %s, the attempt crossing the ip threshold must freeze", got.Status.String())
+	}
+
+	_, err := e.svc.Login(ctx, LoginCmd{Login: other.Email, Password: "other-password", IP: "203.0.113.7"})
+	if !errors.Is(err, auth.ErrLoginThrottled) {
+		t.Fatalf("same ip: want ErrLoginThrottled, got %v", err)
+	}
+	if _, err := e.svc.Login(ctx, LoginCmd{Login: other.Email, Password: "other-password", IP: "198.51.100.4"}); err != nil {
+		t.Fatalf("other ip: %v", err)
+	}
+}
+
+func TestLoginUnknownLoginLooksLikeWrongPassword(t *testing.T) {
+	e := newTestEnv(t, Config{})
+	ctx := context.Background()
+	e.addPlayer(t, "p@example.com", "right-password")
+
+	_, errUnknown := e.svc.Login(ctx, LoginCmd{Login: "nobody@example.com", Password: "right-password"})
+	_, errWrong := e.svc.Login(ctx, LoginCmd{Login: "p@example.com", Password: "wrong-password"})
+	if !errors.Is(errUnknown, auth.ErrInvalidCredentials) || !errors.Is(errWrong, auth.ErrInvalidCredentials) {
+		t.Fatalf("unknown: %v, wrong: %v", errUnknown, errWrong)
+	}
+	if e.hasher.verifies != 2 {
+		t.Fatalf("%d password checks, want one per login", e.hasher.verifies)
+	}
+}
+
+func TestLoginChecksStatusAfterPassword(t *testing.T) {
+	e := newTestEnv(t, Config{})
+	ctx := context.Background()
+	p := e.addPlayer(t, "p@example.com", "right-password")
+	p.Status = player.StatusBlocked
+	e.players.add(p)
+
+	if _, err := e.svc.Login(ctx, LoginCmd{Login: p.Email, Password: "wrong-password"}); !errors.Is(err, auth.ErrInvalidCredentials) {
+		t.Fatalf("wrong password: want ErrInvalidCredentials, got %v", err)
+	}
+	if _, err := e.svc.Login(ctx, LoginCmd{Login: p.Email, Password: "right-password"}); !errors.Is(err, auth.ErrPlayerInactive) {
+		t.Fatalf("right password: want ErrPlayerInactive, got %v", err)
+	}
+	if e.sessions.active(e.clock.Now()) != 0 {
+		t.Fatal("session started for a blocked player")
+	}
+
+	want := []auth.LoginOutcome{auth.LoginBadPassword, auth.LoginInactive}
+	got := e.logins.outcomes()
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("login history %v, want %v", got, want)
+	}
+}
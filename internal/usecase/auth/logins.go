@@ -0,0 +1,109 @@
+package authuc
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/auth"
+	"players_service/internal/domain/player"
+)
+
+const (
+	DefaultLoginsLimit = 50
+	MaxLoginsLimit     = 500
+)
+
+type ListLoginsQuery struct {
+	PlayerID uuid.UUID
+	Cursor   string // opaque, from LoginPage.NextCursor
+	Limit    int    // 0 means DefaultLoginsLimit
+}
+
+type LoginPage struct {
+	Items      []auth.LoginRecord
+	NextCursor string // empty when there are no more records
+}
+
+// ListLogins pages through the login history of a player, newest first.
+func (s *Service) ListLogins(ctx context.Context, q ListLoginsQuery) (LoginPage, error) {
+	f := auth.LoginFilter{PlayerID: q.PlayerID, Limit: q.Limit}
+	if f.Limit == 0 {
+		f.Limit = DefaultLoginsLimit
+	}
+	if f.Limit < 1 || f.Limit > MaxLoginsLimit {
+		return LoginPage{}, fmt.Errorf("%w: limit must be in [1, %d]", player.ErrValidation, MaxLoginsLimit)
+	}
+	if q.Cursor != "" {
+		c, err := decodeLoginCursor(q.Cursor)
+		if err != nil {
+			return LoginPage{}, err
+		}
+		f.After = &c
+	}
+
+	// 404 for unknown players instead of an empty page
+	if _, err := s.players.GetByID(ctx, q.PlayerID); err != nil {
+		return LoginPage{}, err
+	}
+
+	want := f.Limit
+	f.Limit++ // one extra row tells whether there is a next page
+	items, err := s.logins.List(ctx, f)
+	if err != nil {
+		return LoginPage{}, err
+	}
+
+	page := LoginPage{Items: items}
+	if len(items) > want {
+		page.Items = items[:want]
+		last := page.Items[want-1]
+		page.NextCursor = encodeLoginCursor(auth.LoginCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	return page, nil
+}
+
+// SharedIPs finds other players who logged in from the IPs the player used.
+func (s *Service) SharedIPs(ctx context.Context, playerID uuid.UUID, limit int) ([]auth.SharedIP, error) {
+	if limit == 0 {
+		limit = DefaultLoginsLimit
+	}
+	if limit < 1 || limit > MaxLoginsLimit {
+		return nil, fmt.Errorf("%w: limit must be in [1, %d]", player.ErrValidation, MaxLoginsLimit)
+	}
+	if _, err := s.players.GetByID(ctx, playerID); err != nil {
+		return nil, err
+	}
+	return s.logins.SharedIPs(ctx, playerID, limit)
+}
+
+func encodeLoginCursor(c auth.LoginCursor) string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeLoginCursor(s string) (auth.LoginCursor, error) {
+	bad := fmt.Errorf("%w: bad cursor", player.ErrValidation)
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return auth.LoginCursor{}, bad
+	}
+	at, idStr, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return auth.LoginCursor{}, bad
+	}
+	t, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return auth.LoginCursor{}, bad
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return auth.LoginCursor{}, bad
+	}
+	return auth.LoginCursor{CreatedAt: t, ID: id}, nil
+}
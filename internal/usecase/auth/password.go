@@ -0,0 +1,195 @@
+package authuc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/auth"
+	"players_service/internal/domain/player"
+	"players_service/internal/domain/verification"
+)
+
+// Password reset takes three steps: RequestPasswordReset sends a code to a
+// contact, ConfirmPasswordReset trades the code for a single-use reset token
+// and CompletePasswordReset sets the new password with that token.
+
+type ResetRequestCmd struct {
+	Email string
+	Phone string
+	IP    string
+}
+
+// RequestPasswordReset answers the same whether the account exists or not,
+// the code is only sent in the former case. Both go through the same rate
+// limits and store a code, so neither the answer nor throttling tells them apart.
+func (s *Service) RequestPasswordReset(ctx context.Context, cmd ResetRequestCmd) (int, error) {
+	dst, err := codeDestination(cmd.Email, cmd.Phone)
+	if err != nil {
+		return 0, err
+	}
+
+	p, err := s.findByLogin(ctx, dst.Value)
+	if errors.Is(err, player.ErrNotFound) {
+		res, err := s.codes.Pretend(ctx, verification.PurposeReset, dst, parseIP(cmd.IP))
+		if err != nil {
+			return 0, err
+		}
+		return res.CodeLength, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := s.codes.Send(ctx, verification.PurposeReset, dst, parseIP(cmd.IP))
+	if err != nil {
+		return 0, err
+	}
+	if err := s.audit.Append(ctx, auth.NewPasswordAudit(p.ID, auth.AuditResetRequested, parseIP(cmd.IP), s.clock.Now())); err != nil {
+		return 0, err
+	}
+	return res.CodeLength, nil
+}
+
+type ResetConfirmCmd struct {
+	Email string
+	Phone string
+	Code  string
+	IP    string
+}
+
+type ResetToken struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// ConfirmPasswordReset checks the code and issues a reset token. For an
+// unknown account no code exists, so it fails like a wrong code does.
+func (s *Service) ConfirmPasswordReset(ctx context.Context, cmd ResetConfirmCmd) (ResetToken, error) {
+	dst, err := codeDestination(cmd.Email, cmd.Phone)
+	if err != nil {
+		return ResetToken{}, err
+	}
+	if err := s.codes.Verify(ctx, verification.PurposeReset, dst, cmd.Code); err != nil {
+		return ResetToken{}, err
+	}
+
+	p, err := s.findByLogin(ctx, dst.Value)
+	if errors.Is(err, player.ErrNotFound) {
+		return ResetToken{}, verification.ErrInvalidCode
+	}
+	if err != nil {
+		return ResetToken{}, err
+	}
+
+	pr, tok, err := auth.NewPasswordReset(p.ID, parseIP(cmd.IP), s.cfg.ResetTTL, s.clock.Now())
+	if err != nil {
+		return ResetToken{}, err
+	}
+	if err := s.resets.Create(ctx, pr); err != nil {
+		return ResetToken{}, err
+	}
+	return ResetToken{Token: tok, ExpiresAt: pr.ExpiresAt}, nil
+}
+
+type ResetCompleteCmd struct {
+	Token    string
+	Password string
+	IP       string
+}
+
+// CompletePasswordReset sets the password and ends every session of the player.
+func (s *Service) CompletePasswordReset(ctx context.Context, cmd ResetCompleteCmd) error {
+	if err := auth.ValidatePassword(cmd.Password); err != nil {
+		return err
+	}
+
+	pr, err := s.resets.GetByTokenHash(ctx, auth.HashRefreshToken(cmd.Token))
+	if errors.Is(err, player.ErrNotFound) {
+		return auth.ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+	if !pr.Usable(s.clock.Now()) {
+		return auth.ErrInvalidResetToken
+	}
+
+	hash, err := s.hasher.Hash(cmd.Password)
+	if err != nil {
+		return err
+	}
+
+	return s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		now := s.clock.Now()
+		ok, err := s.resets.MarkUsed(ctx, pr.ID, now)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return auth.ErrInvalidResetToken
+		}
+		return s.setPassword(ctx, pr.PlayerID, uuid.Nil, hash, auth.AuditResetCompleted, cmd.IP, now)
+	})
+}
+
+type ChangePasswordCmd struct {
+	PlayerID    uuid.UUID
+	SessionID   uuid.UUID // kept alive, other sessions are ended
+	Password    string    // current one
+	NewPassword string
+	IP          string
+}
+
+func (s *Service) ChangePassword(ctx context.Context, cmd ChangePasswordCmd) error {
+	if err := auth.ValidatePassword(cmd.NewPassword); err != nil {
+		return err
+	}
+
+	cred, err := s.passwords.Get(ctx, cmd.PlayerID)
+	if err != nil && !errors.Is(err, player.ErrNotFound) {
+		return err
+	}
+	if cred.Hash == "" {
+		// players without a password (social logins) set one through reset
+		return auth.ErrWrongPassword
+	}
+	ok, err := s.hasher.Verify(cmd.Password, cred.Hash)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return auth.ErrWrongPassword
+	}
+
+	hash, err := s.hasher.Hash(cmd.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	return s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		return s.setPassword(ctx, cmd.PlayerID, cmd.SessionID, hash, auth.AuditChanged, cmd.IP, s.clock.Now())
+	})
+}
+
+// setPassword stores hash and revokes the player's sessions except keepSession
+// (uuid.Nil revokes all). Must be called inside a transaction.
+func (s *Service) setPassword(ctx context.Context, playerID, keepSession uuid.UUID, hash, action, ip string, now time.Time) error {
+	if err := s.passwords.Upsert(ctx, auth.NewPasswordCredential(playerID, hash, now)); err != nil {
+		return err
+	}
+
+	var err error
+	if keepSession == uuid.Nil {
+		_, err = s.sessions.RevokeAllForPlayer(ctx, playerID, now, auth.RevokeReset)
+	} else {
+		_, err = s.sessions.RevokeOthers(ctx, playerID, keepSession, now, auth.RevokeChange)
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.audit.Append(ctx, auth.NewPasswordAudit(playerID, action, parseIP(ip), now))
+}
@@ -0,0 +1,53 @@
+package authuc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"players_service/internal/domain/verification"
+)
+
+func TestRequestPasswordResetDoesNotRevealAccounts(t *testing.T) {
+	e := newTestEnv(t, Config{})
+	e.codes.maxPerDest = 2
+	ctx := context.Background()
+	e.addPlayer(t, "known@example.com", "right-password")
+
+	for _, email := range []string{"known@example.com", "unknown@example.com"} {
+		for i := 0; i < 2; i++ {
+			n, err := e.svc.RequestPasswordReset(ctx, ResetRequestCmd{Email: email, IP: "203.0.113.7"})
+			if err != nil || n != 6 {
+				t.Fatalf("%s request %d: got %d, %v", email, i+1, n, err)
+			}
+		}
+		// the unknown account is throttled exactly like the known one
+		_, err := e.svc.RequestPasswordReset(ctx, ResetRequestCmd{Email: email, IP: "203.0.113.7"})
+		if !errors.Is(err, verification.ErrTooManyRequests) {
+			t.Fatalf("%s over the limit: want ErrTooManyRequests, got %v", email, err)
+		}
+	}
+
+	for _, dst := range e.codes.delivered {
+		if dst.Value == "unknown@example.com" {
+			t.Fatal("code delivered to an address without an account")
+		}
+	}
+	if len(e.codes.delivered) != 2 {
+		t.Fatalf("%d codes delivered, want 2", len(e.codes.delivered))
+	}
+	if len(e.audit.entries) != 2 {
+		t.Fatalf("%d audit entries, want 2", len(e.audit.entries))
+	}
+}
+
+func TestConfirmPasswordResetUnknownAccount(t *testing.T) {
+	e := newTestEnv(t, Config{})
+	ctx := context.Background()
+
+	// even a code that checks out gives nothing for an unknown account
+	_, err := e.svc.ConfirmPasswordReset(ctx, ResetConfirmCmd{Email: "unknown@example.com", Code: "000000"})
+	if !errors.Is(err, verification.ErrInvalidCode) {
+		t.Fatalf("want ErrInvalidCode, got %v", err)
+	}
+}
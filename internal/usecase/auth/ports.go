@@ -0,0 +1,134 @@
+package authuc
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/auth"
+	"players_service/internal/domain/player"
+	"players_service/internal/domain/verification"
+	playeruc "players_service/internal/usecase/player"
+	verificationuc "players_service/internal/usecase/verification"
+)
+
+// PlayerRepository is the part of the player store auth needs.
+type PlayerRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*player.Player, error)
+	GetByEmail(ctx context.Context, email string) (*player.Player, error)
+	GetByPhone(ctx context.Context, phone string) (*player.Player, error)
+	Update(ctx context.Context, p *player.Player) error
+}
+
+// PlayerRegistrar creates players, implemented by playeruc.Service.
+type PlayerRegistrar interface {
+	CreatePlayer(ctx context.Context, cmd playeruc.CreatePlayerCmd) (*player.Player, error)
+}
+
+// ContactVerifier records confirmed contacts, implemented by playeruc.Service.
+type ContactVerifier interface {
+	VerifyEmail(ctx context.Context, playerID uuid.UUID, email string) (*player.Player, error)
+	VerifyPhone(ctx context.Context, playerID uuid.UUID, phone string) (*player.Player, error)
+	AddCredential(ctx context.Context, playerID uuid.UUID, kind player.CredentialKind, value string) (player.Credential, error)
+}
+
+// StatusChanger changes player statuses, implemented by playeruc.Service.
+type StatusChanger interface {
+	ChangeStatus(ctx context.Context, cmd playeruc.ChangeStatusCmd) (*player.Player, player.PlayerStatusEvent, error)
+}
+
+type PasswordRepository interface {
+	Get(ctx context.Context, playerID uuid.UUID) (auth.PasswordCredential, error)
+	Upsert(ctx context.Context, c auth.PasswordCredential) error
+}
+
+type SessionRepository interface {
+	Create(ctx context.Context, s auth.Session) error
+	GetByID(ctx context.Context, id uuid.UUID) (auth.Session, error)
+	GetByRefreshHash(ctx context.Context, hash string) (auth.Session, error)
+	// Revoke reports false when the session was already revoked.
+	Revoke(ctx context.Context, id uuid.UUID, at time.Time, reason string) (bool, error)
+	RevokeFamily(ctx context.Context, familyID uuid.UUID, at time.Time, reason string) (int, error)
+	RevokeAllForPlayer(ctx context.Context, playerID uuid.UUID, at time.Time, reason string) (int, error)
+	RevokeOthers(ctx context.Context, playerID, keepID uuid.UUID, at time.Time, reason string) (int, error)
+}
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, r auth.PasswordReset) error
+	GetByTokenHash(ctx context.Context, hash string) (auth.PasswordReset, error)
+	// MarkUsed reports false when the reset was already used.
+	MarkUsed(ctx context.Context, id uuid.UUID, at time.Time) (bool, error)
+}
+
+type SocialAccountRepository interface {
+	Get(ctx context.Context, provider auth.Provider, subject string) (auth.SocialAccount, error)
+	ListByPlayer(ctx context.Context, playerID uuid.UUID) ([]auth.SocialAccount, error)
+	// LockByPlayer is ListByPlayer that also locks the rows until the
+	// transaction ends. Must run inside one.
+	LockByPlayer(ctx context.Context, playerID uuid.UUID) ([]auth.SocialAccount, error)
+	// Create returns ErrConflict when the identity or the player's provider slot is taken.
+	Create(ctx context.Context, a auth.SocialAccount) error
+	Delete(ctx context.Context, playerID uuid.UUID, provider auth.Provider) error
+}
+
+// ProviderVerifier checks an ID token of one provider (signature, issuer,
+// audience, expiry) and returns the identity it asserts.
+type ProviderVerifier interface {
+	Verify(ctx context.Context, idToken string) (auth.ExternalIdentity, error)
+}
+
+type LoginFailureRepository interface {
+	Record(ctx context.Context, f auth.LoginFailure) error
+	CountForPlayer(ctx context.Context, playerID uuid.UUID, since time.Time) (int, error)
+	CountForIP(ctx context.Context, ip net.IP, since time.Time) (int, error)
+	ClearForPlayer(ctx context.Context, playerID uuid.UUID) error
+}
+
+type LoginHistoryRepository interface {
+	Append(ctx context.Context, l auth.LoginRecord) error
+	// List returns records newest first, strictly after f.After when it is set.
+	List(ctx context.Context, f auth.LoginFilter) ([]auth.LoginRecord, error)
+	SharedIPs(ctx context.Context, playerID uuid.UUID, limit int) ([]auth.SharedIP, error)
+}
+
+type PasswordAuditRepository interface {
+	Append(ctx context.Context, a auth.PasswordAudit) error
+}
+
+// AccessTokens signs access tokens. Parse checks the signature only.
+type AccessTokens interface {
+	Issue(c auth.AccessClaims) (string, error)
+	Parse(tok string) (auth.AccessClaims, error)
+}
+
+// IntegrationTokens signs game provider tokens. Parse checks the signature only.
+type IntegrationTokens interface {
+	Issue(c auth.IntegrationClaims) (string, error)
+	Parse(tok string) (auth.IntegrationClaims, error)
+}
+
+type PasswordHasher interface {
+	Hash(pw string) (string, error)
+	Verify(pw, hash string) (bool, error)
+}
+
+type UnitOfWork interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type Clock interface {
+	Now() time.Time
+}
+
+// Codes issues and checks one-time codes, implemented by verificationuc.Service.
+type Codes interface {
+	Send(ctx context.Context, purpose verification.Purpose, dst verification.Destination, ip net.IP) (verificationuc.SendResult, error)
+	// Pretend goes through Send, limits included, without delivering a code.
+	Pretend(ctx context.Context, purpose verification.Purpose, dst verification.Destination, ip net.IP) (verificationuc.SendResult, error)
+	Verify(ctx context.Context, purpose verification.Purpose, dst verification.Destination, value string) error
+	// Check counts the guess like Verify but leaves the code to Consume.
+	Check(ctx context.Context, purpose verification.Purpose, dst verification.Destination, value string) (uuid.UUID, error)
+	Consume(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,37 @@
+package authuc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"players_service/internal/domain/player"
+	"players_service/internal/domain/verification"
+)
+
+func TestRegisterRejectionKeepsCode(t *testing.T) {
+	e := newTestEnv(t, Config{})
+	ctx := context.Background()
+	e.addPlayer(t, "taken@example.com", "right-password")
+	dst, _ := verification.EmailDestination("taken@example.com")
+
+	_, err := e.svc.Register(ctx, RegisterCmd{Email: "taken@example.com", Password: "new-password-1", Code: "000000"})
+	if !errors.Is(err, player.ErrConflict) {
+		t.Fatalf("duplicate email: want ErrConflict, got %v", err)
+	}
+	if e.codes.used(dst) {
+		t.Fatal("a rejected registration used up the code")
+	}
+
+	dst, _ = verification.EmailDestination("new@example.com")
+	p, err := e.svc.Register(ctx, RegisterCmd{Email: "new@example.com", Password: "new-password-1", Code: "000000", Currency: "eur"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.codes.used(dst) || p.EmailVerifiedAt.IsZero() {
+		t.Fatalf("registered: code used %v, email verified at %v", e.codes.used(dst), p.EmailVerifiedAt)
+	}
+	if _, err := e.svc.Register(ctx, RegisterCmd{Email: "new@example.com", Password: "new-password-1", Code: "000000"}); err == nil {
+		t.Fatal("registered twice")
+	}
+}
@@ -0,0 +1,354 @@
+package authuc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/auth"
+	"players_service/internal/domain/player"
+	"players_service/internal/domain/verification"
+	playeruc "players_service/internal/usecase/player"
+)
+
+type Service struct {
+	uow       UnitOfWork
+	players   PlayerRepository
+	registrar PlayerRegistrar
+	contacts  ContactVerifier
+	statuses  StatusChanger
+	passwords PasswordRepository
+	sessions  SessionRepository
+	resets    PasswordResetRepository
+	audit     PasswordAuditRepository
+	social    SocialAccountRepository
+	failures  LoginFailureRepository
+	logins    LoginHistoryRepository
+	verifiers map[auth.Provider]ProviderVerifier
+	hasher    PasswordHasher
+	tokens    AccessTokens
+	intTokens IntegrationTokens
+	codes     Codes
+	clock     Clock
+	cfg       Config
+
+	// dummyHash is verified against when the login is unknown, so both
+	// branches cost the same and response time does not reveal accounts.
+	dummyHash string
+}
+
+type Deps struct {
+	UoW       UnitOfWork
+	Players   PlayerRepository
+	Registrar PlayerRegistrar
+	Contacts  ContactVerifier
+	Statuses  StatusChanger
+	Passwords PasswordRepository
+	Sessions  SessionRepository
+	Resets    PasswordResetRepository
+	Audit     PasswordAuditRepository
+	Social    SocialAccountRepository
+	Failures  LoginFailureRepository
+	Logins    LoginHistoryRepository
+	Verifiers map[auth.Provider]ProviderVerifier // providers without a verifier are disabled
+	Hasher    PasswordHasher
+	Tokens    AccessTokens
+	IntTokens IntegrationTokens
+	Codes     Codes
+	Clock     Clock
+}
+
+type Config struct {
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+	ResetTTL   time.Duration // lifetime of a password reset token
+	// IntegrationTTL is the lifetime of game provider tokens, keep it short:
+	// they are only used to open a game.
+	IntegrationTTL time.Duration
+	Lockout        LockoutConfig
+}
+
+// LockoutConfig limits failed logins, counted in a sliding Window. Crossing
+// either threshold freezes the player the failing attempt was for, and logins
+// are refused until enough failures have left the window.
+type LockoutConfig struct {
+	Window       time.Duration
+	MaxPerPlayer int           // failures that freeze the player
+	MaxPerIP     int           // failures from one IP that freeze the player and refuse the IP
+	UnlockAfter  time.Duration // freeze length, 0 leaves unfreezing to an admin
+}
+
+func (c Config) withDefaults() Config {
+	if c.AccessTTL <= 0 {
+		c.AccessTTL = 15 * time.Minute
+	}
+	if c.RefreshTTL <= 0 {
+		c.RefreshTTL = 30 * 24 * time.Hour
+	}
+	if c.ResetTTL <= 0 {
+		c.ResetTTL = 15 * time.Minute
+	}
+	if c.IntegrationTTL <= 0 {
+		c.IntegrationTTL = 5 * time.Minute
+	}
+	if c.Lockout.Window <= 0 {
+		c.Lockout.Window = 15 * time.Minute
+	}
+	if c.Lockout.MaxPerPlayer <= 0 {
+		c.Lockout.MaxPerPlayer = 5
+	}
+	if c.Lockout.MaxPerIP <= 0 {
+		c.Lockout.MaxPerIP = 20
+	}
+	return c
+}
+
+func New(d Deps, cfg Config) (*Service, error) {
+	dummy, err := d.Hasher.Hash("dummy-password-for-timing")
+	if err != nil {
+		return nil, err
+	}
+	return &Service{
+		uow:       d.UoW,
+		players:   d.Players,
+		registrar: d.Registrar,
+		contacts:  d.Contacts,
+		statuses:  d.Statuses,
+		passwords: d.Passwords,
+		sessions:  d.Sessions,
+		resets:    d.Resets,
+		audit:     d.Audit,
+		social:    d.Social,
+		failures:  d.Failures,
+		logins:    d.Logins,
+		verifiers: d.Verifiers,
+		hasher:    d.Hasher,
+		tokens:    d.Tokens,
+		intTokens: d.IntTokens,
+		codes:     d.Codes,
+		clock:     d.Clock,
+		cfg:       cfg.withDefaults(),
+		dummyHash: dummy,
+	}, nil
+}
+
+var reCurrency = regexp.MustCompile(`^[A-Z]{3,5}$`)
+
+type RegisterCmd struct {
+	Email          string
+	Phone          string
+	Password       string
+	Code           string    // sent by SendRegistrationCode
+	BirthDate      time.Time // optional unless the country requires it
+	CountryCode    string
+	Currency       string
+	Locale         string
+	TimeZone       string
+	RegistrationIP string
+	Metadata       map[string]any
+}
+
+// Register creates a player together with a password credential. The
+// contact the code was sent to must match the registration data.
+func (s *Service) Register(ctx context.Context, cmd RegisterCmd) (*player.Player, error) {
+	if err := auth.ValidatePassword(cmd.Password); err != nil {
+		return nil, err
+	}
+	dst, err := codeDestination(cmd.Email, cmd.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := make(map[string]any, len(cmd.Metadata)+1)
+	for k, v := range cmd.Metadata {
+		meta[k] = v
+	}
+	if c := strings.ToUpper(strings.TrimSpace(cmd.Currency)); c != "" {
+		if !reCurrency.MatchString(c) {
+			return nil, fmt.Errorf("%w: bad currency %s", player.ErrValidation, cmd.Currency)
+		}
+		meta["currency"] = c
+	}
+
+	// outside the transaction: a wrong guess must be counted even if we fail
+	// later; the code is consumed with the insert, so a rejected registration
+	// leaves it usable
+	codeID, err := s.codes.Check(ctx, verification.PurposeRegistration, dst, cmd.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	// hash outside the transaction, it is deliberately slow
+	hash, err := s.hasher.Hash(cmd.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	var created *player.Player
+	err = s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		p, err := s.registrar.CreatePlayer(ctx, playeruc.CreatePlayerCmd{
+			Email:          cmd.Email,
+			Phone:          cmd.Phone,
+			BirthDate:      cmd.BirthDate,
+			CountryCode:    cmd.CountryCode,
+			Locale:         cmd.Locale,
+			TimeZone:       cmd.TimeZone,
+			RegistrationIP: cmd.RegistrationIP,
+			Metadata:       meta,
+			RegisteredAt:   s.clock.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.passwords.Upsert(ctx, auth.NewPasswordCredential(p.ID, hash, s.clock.Now())); err != nil {
+			return err
+		}
+		// the code proved the contact it was sent to
+		p, err = s.markVerified(ctx, p.ID, dst)
+		if err != nil {
+			return err
+		}
+		if err := s.codes.Consume(ctx, codeID); err != nil {
+			return err
+		}
+		created = p
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+type LoginCmd struct {
+	Login     string // email or phone
+	Password  string
+	IP        string
+	UserAgent string
+}
+
+type LoginResult struct {
+	Player  *player.Player
+	Tokens  auth.Tokens
+	Created bool // set by SocialLogin when it registered the player
+}
+
+// Login checks the password of the player identified by email or phone and
+// starts a session. Unknown login and wrong password yield the same ErrInvalidCredentials.
+func (s *Service) Login(ctx context.Context, cmd LoginCmd) (LoginResult, error) {
+	ip := parseIP(cmd.IP)
+	if err := s.checkIPFailures(ctx, ip); err != nil {
+		return LoginResult{}, err
+	}
+
+	p, err := s.findByLogin(ctx, cmd.Login)
+	if err != nil && !errors.Is(err, player.ErrNotFound) {
+		return LoginResult{}, err
+	}
+
+	var cred auth.PasswordCredential
+	if p != nil {
+		if err := s.checkPlayerFailures(ctx, p.ID); err != nil {
+			if errors.Is(err, auth.ErrLoginThrottled) {
+				if err := s.recordLogin(ctx, p.ID, auth.MethodPassword, auth.LoginThrottled, cmd.IP, cmd.UserAgent); err != nil {
+					return LoginResult{}, err
+				}
+			}
+			return LoginResult{}, err
+		}
+		cred, err = s.passwords.Get(ctx, p.ID)
+		if err != nil && !errors.Is(err, player.ErrNotFound) {
+			return LoginResult{}, err
+		}
+	}
+
+	hash := cred.Hash
+	if hash == "" {
+		hash = s.dummyHash
+	}
+	ok, err := s.hasher.Verify(cmd.Password, hash)
+	if err != nil {
+		return LoginResult{}, err
+	}
+	if !ok || cred.Hash == "" {
+		if p != nil {
+			if err := s.recordLogin(ctx, p.ID, auth.MethodPassword, auth.LoginBadPassword, cmd.IP, cmd.UserAgent); err != nil {
+				return LoginResult{}, err
+			}
+		}
+		if err := s.loginFailed(ctx, p, ip); err != nil {
+			return LoginResult{}, err
+		}
+		return LoginResult{}, auth.ErrInvalidCredentials
+	}
+
+	return s.signIn(ctx, p.ID, auth.MethodPassword, cmd.IP, cmd.UserAgent)
+}
+
+// signIn records the login of an authenticated player and starts a session.
+// The status is checked only here, after the credentials, so it does not leak.
+func (s *Service) signIn(ctx context.Context, playerID uuid.UUID, method auth.LoginMethod, ip, userAgent string) (LoginResult, error) {
+	var res LoginResult
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		// re-read inside the tx to get a fresh version for the update
+		p, err := s.players.GetByID(ctx, playerID)
+		if err != nil {
+			return err
+		}
+		if !p.CanLogin() {
+			return auth.ErrPlayerInactive
+		}
+		if err := s.recordLogin(ctx, p.ID, method, auth.LoginSucceeded, ip, userAgent); err != nil {
+			return err
+		}
+		now := s.clock.Now()
+		p.MarkLogin(now)
+		if err := s.players.Update(ctx, p); err != nil {
+			return err
+		}
+		if err := s.failures.ClearForPlayer(ctx, p.ID); err != nil {
+			return err
+		}
+
+		tokens, err := s.startSession(ctx, p.ID, uuid.Nil, ip, userAgent, now)
+		if err != nil {
+			return err
+		}
+		res = LoginResult{Player: p, Tokens: tokens}
+		return nil
+	})
+	if errors.Is(err, auth.ErrPlayerInactive) {
+		// outside the rolled back transaction, so the attempt stays on record
+		if err := s.recordLogin(ctx, playerID, method, auth.LoginInactive, ip, userAgent); err != nil {
+			return LoginResult{}, err
+		}
+	}
+	if err != nil {
+		return LoginResult{}, err
+	}
+	return res, nil
+}
+
+func (s *Service) recordLogin(ctx context.Context, playerID uuid.UUID, method auth.LoginMethod, outcome auth.LoginOutcome, ip, userAgent string) error {
+	return s.logins.Append(ctx, auth.NewLoginRecord(playerID, method, outcome, parseIP(ip), userAgent, s.clock.Now()))
+}
+
+func (s *Service) findByLogin(ctx context.Context, login string) (*player.Player, error) {
+	login = strings.TrimSpace(login)
+	if login == "" {
+		return nil, player.ErrNotFound
+	}
+	if strings.Contains(login, "@") {
+		return s.players.GetByEmail(ctx, strings.ToLower(login))
+	}
+	phone, err := player.NormalizePhone(login)
+	if err != nil {
+		return nil, player.ErrNotFound
+	}
+	return s.players.GetByPhone(ctx, phone)
+}
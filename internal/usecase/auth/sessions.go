@@ -0,0 +1,177 @@
+package authuc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/auth"
+	"players_service/internal/domain/player"
+	playeruc "players_service/internal/usecase/player"
+)
+
+// startSession creates a session (in the family, or a new one for uuid.Nil)
+// and issues its token pair. Must be called inside a transaction.
+func (s *Service) startSession(ctx context.Context, playerID, familyID uuid.UUID, ip, userAgent string, now time.Time) (auth.Tokens, error) {
+	sess, refresh, err := auth.NewSession(playerID, familyID, parseIP(ip), userAgent, s.cfg.RefreshTTL, now)
+	if err != nil {
+		return auth.Tokens{}, err
+	}
+	if err := s.sessions.Create(ctx, sess); err != nil {
+		return auth.Tokens{}, err
+	}
+
+	accessExp := now.Add(s.cfg.AccessTTL)
+	access, err := s.tokens.Issue(auth.AccessClaims{
+		PlayerID:  playerID,
+		SessionID: sess.ID,
+		IssuedAt:  now,
+		ExpiresAt: accessExp,
+	})
+	if err != nil {
+		return auth.Tokens{}, err
+	}
+
+	return auth.Tokens{
+		AccessToken:      access,
+		AccessExpiresAt:  accessExp,
+		RefreshToken:     refresh,
+		RefreshExpiresAt: sess.ExpiresAt,
+	}, nil
+}
+
+type RefreshCmd struct {
+	RefreshToken string
+	IP           string
+	UserAgent    string
+}
+
+// Refresh rotates a refresh token. Presenting an already rotated token means it
+// leaked, so the whole session family is revoked.
+func (s *Service) Refresh(ctx context.Context, cmd RefreshCmd) (auth.Tokens, error) {
+	if strings.TrimSpace(cmd.RefreshToken) == "" {
+		return auth.Tokens{}, auth.ErrInvalidToken
+	}
+
+	var tokens auth.Tokens
+	reused := false
+
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		now := s.clock.Now()
+
+		sess, err := s.sessions.GetByRefreshHash(ctx, auth.HashRefreshToken(cmd.RefreshToken))
+		if err != nil {
+			if errors.Is(err, player.ErrNotFound) {
+				return auth.ErrInvalidToken
+			}
+			return err
+		}
+
+		if !sess.RevokedAt.IsZero() {
+			if sess.RevokeReason == auth.RevokeRotated {
+				reused = true
+			}
+			return auth.ErrInvalidToken
+		}
+		if !sess.Active(now) {
+			return auth.ErrInvalidToken
+		}
+
+		p, err := s.players.GetByID(ctx, sess.PlayerID)
+		if err != nil {
+			return err
+		}
+		if !p.CanLogin() {
+			return auth.ErrPlayerInactive
+		}
+
+		ok, err := s.sessions.Revoke(ctx, sess.ID, now, auth.RevokeRotated)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			// a concurrent refresh won the race with the same token
+			reused = true
+			return auth.ErrInvalidToken
+		}
+
+		tokens, err = s.startSession(ctx, sess.PlayerID, sess.FamilyID, cmd.IP, cmd.UserAgent, now)
+		return err
+	})
+
+	if reused {
+		// outside the failed tx, the revocation must stick
+		if sess, gerr := s.sessions.GetByRefreshHash(ctx, auth.HashRefreshToken(cmd.RefreshToken)); gerr == nil {
+			if _, rerr := s.sessions.RevokeFamily(ctx, sess.FamilyID, s.clock.Now(), auth.RevokeReuse); rerr != nil {
+				return auth.Tokens{}, rerr
+			}
+		}
+	}
+	if err != nil {
+		return auth.Tokens{}, err
+	}
+	return tokens, nil
+}
+
+// Authenticate validates an access token and that its session is still alive,
+// so logout and kicks take effect immediately.
+func (s *Service) Authenticate(ctx context.Context, accessToken string) (auth.AccessClaims, error) {
+	c, err := s.tokens.Parse(accessToken)
+	if err != nil {
+		return auth.AccessClaims{}, auth.ErrUnauthenticated
+	}
+	now := s.clock.Now()
+	if !now.Before(c.ExpiresAt) {
+		return auth.AccessClaims{}, auth.ErrUnauthenticated
+	}
+
+	sess, err := s.sessions.GetByID(ctx, c.SessionID)
+	if err != nil {
+		if errors.Is(err, player.ErrNotFound) {
+			return auth.AccessClaims{}, auth.ErrUnauthenticated
+		}
+		return auth.AccessClaims{}, err
+	}
+	if !sess.Active(now) || sess.PlayerID != c.PlayerID {
+		return auth.AccessClaims{}, auth.ErrUnauthenticated
+	}
+	return c, nil
+}
+
+func (s *Service) Logout(ctx context.Context, sessionID uuid.UUID) error {
+	_, err := s.sessions.Revoke(ctx, sessionID, s.clock.Now(), auth.RevokeLogout)
+	return err
+}
+
+// Kick ends every session of the given players and returns how many were revoked.
+func (s *Service) Kick(ctx context.Context, playerIDs []uuid.UUID) (int, error) {
+	if len(playerIDs) > playeruc.MaxBatchSize {
+		return 0, fmt.Errorf("%w: at most %d ids per request", player.ErrValidation, playeruc.MaxBatchSize)
+	}
+
+	total := 0
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		now := s.clock.Now()
+		for _, id := range playerIDs {
+			n, err := s.sessions.RevokeAllForPlayer(ctx, id, now, auth.RevokeKick)
+			if err != nil {
+				return err
+			}
+			total += n
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func parseIP(s string) net.IP {
+	return net.ParseIP(strings.TrimSpace(s))
+}
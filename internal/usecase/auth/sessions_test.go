@@ -0,0 +1,44 @@
+package authuc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"players_service/internal/domain/auth"
+)
+
+func TestRefreshReuseRevokesFamily(t *testing.T) {
+	e := newTestEnv(t, Config{})
+	ctx := context.Background()
+	p := e.addPlayer(t, "p@example.com", "right-password")
+
+	other, err := e.svc.Login(ctx, LoginCmd{Login: p.Email, Password: "right-password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := e.svc.Login(ctx, LoginCmd{Login: p.Email, Password: "right-password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stolen := res.Tokens.RefreshToken
+
+	rotated, err := e.svc.Refresh(ctx, RefreshCmd{RefreshToken: stolen})
+	if err != nil {
+		t.Fatalf("first refresh: %v", err)
+	}
+	if _, err := e.svc.Refresh(ctx, RefreshCmd{RefreshToken: stolen}); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Fatalf("reuse: want ErrInvalidToken, got %v", err)
+	}
+
+	// the rotated token belongs to the same family and dies with it
+	if _, err := e.svc.Refresh(ctx, RefreshCmd{RefreshToken: rotated.RefreshToken}); !errors.Is(err, auth.ErrInvalidToken) {
+		t.Fatalf("rotated token after reuse: want ErrInvalidToken, got %v", err)
+	}
+	if e.sessions.active(e.clock.Now()) != 1 {
+		t.Fatalf("%d active sessions, want only the other login left", e.sessions.active(e.clock.Now()))
+	}
+	if _, err := e.svc.Refresh(ctx, RefreshCmd{RefreshToken: other.Tokens.RefreshToken}); err != nil {
+		t.Fatalf("other family: %v", err)
+	}
+}
@@ -0,0 +1,198 @@
+package authuc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/auth"
+	"players_service/internal/domain/player"
+	playeruc "players_service/internal/usecase/player"
+)
+
+func (s *Service) verifyIDToken(ctx context.Context, provider, idToken string) (auth.ExternalIdentity, error) {
+	prov, err := auth.ParseProvider(provider)
+	if err != nil {
+		return auth.ExternalIdentity{}, err
+	}
+	v, ok := s.verifiers[prov]
+	if !ok {
+		return auth.ExternalIdentity{}, fmt.Errorf("%w: provider %s is not enabled", player.ErrValidation, prov)
+	}
+	id, err := v.Verify(ctx, idToken)
+	if err != nil {
+		return auth.ExternalIdentity{}, err
+	}
+	id.Provider = prov
+	return id, nil
+}
+
+func (s *Service) ListSocialAccounts(ctx context.Context, playerID uuid.UUID) ([]auth.SocialAccount, error) {
+	return s.social.ListByPlayer(ctx, playerID)
+}
+
+// LinkSocialAccount attaches the identity behind idToken to the player.
+// An identity already linked to someone else yields ErrConflict.
+func (s *Service) LinkSocialAccount(ctx context.Context, playerID uuid.UUID, provider, idToken string) (auth.SocialAccount, error) {
+	id, err := s.verifyIDToken(ctx, provider, idToken)
+	if err != nil {
+		return auth.SocialAccount{}, err
+	}
+
+	existing, err := s.social.Get(ctx, id.Provider, id.Subject)
+	switch {
+	case err == nil && existing.PlayerID == playerID:
+		return existing, nil
+	case err == nil:
+		return auth.SocialAccount{}, fmt.Errorf("%w: account linked to another player", player.ErrConflict)
+	case !errors.Is(err, player.ErrNotFound):
+		return auth.SocialAccount{}, err
+	}
+
+	a, err := auth.NewSocialAccount(playerID, id, s.clock.Now())
+	if err != nil {
+		return auth.SocialAccount{}, err
+	}
+	if err := s.social.Create(ctx, a); err != nil {
+		return auth.SocialAccount{}, err
+	}
+	return a, nil
+}
+
+// UnlinkSocialAccount is refused with ErrForbidden when the player would be
+// left without a password and without other linked accounts.
+func (s *Service) UnlinkSocialAccount(ctx context.Context, playerID uuid.UUID, provider string) error {
+	prov, err := auth.ParseProvider(provider)
+	if err != nil {
+		return err
+	}
+
+	return s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		// locked, or two unlinks of different providers could each count
+		// the other one and leave the player with nothing
+		accounts, err := s.social.LockByPlayer(ctx, playerID)
+		if err != nil {
+			return err
+		}
+		others, found := 0, false
+		for _, a := range accounts {
+			if a.Provider == prov {
+				found = true
+			} else {
+				others++
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: no %s account", player.ErrNotFound, prov)
+		}
+
+		if others == 0 {
+			_, err := s.passwords.Get(ctx, playerID)
+			if errors.Is(err, player.ErrNotFound) {
+				return fmt.Errorf("%w: last login method", player.ErrForbidden)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		return s.social.Delete(ctx, playerID, prov)
+	})
+}
+
+type SocialLoginCmd struct {
+	Provider  string
+	IDToken   string
+	IP        string
+	UserAgent string
+
+	// used only when the player gets registered
+	BirthDate   time.Time
+	CountryCode string
+	Currency    string
+	Locale      string
+	TimeZone    string
+}
+
+// SocialLogin logs in the player linked to the identity behind the token, or
+// registers one. The provider must then supply an email; an email that
+// belongs to an existing player yields ErrConflict, since linking has to be
+// done by that player after a normal login.
+func (s *Service) SocialLogin(ctx context.Context, cmd SocialLoginCmd) (LoginResult, error) {
+	id, err := s.verifyIDToken(ctx, cmd.Provider, cmd.IDToken)
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	linked, err := s.social.Get(ctx, id.Provider, id.Subject)
+	if err == nil {
+		return s.signIn(ctx, linked.PlayerID, auth.SocialMethod(id.Provider), cmd.IP, cmd.UserAgent)
+	}
+	if !errors.Is(err, player.ErrNotFound) {
+		return LoginResult{}, err
+	}
+
+	if strings.TrimSpace(id.Email) == "" {
+		return LoginResult{}, fmt.Errorf("%w: provider did not share an email", player.ErrValidation)
+	}
+	meta := map[string]any{"signup_provider": string(id.Provider)}
+	if c := strings.ToUpper(strings.TrimSpace(cmd.Currency)); c != "" {
+		if !reCurrency.MatchString(c) {
+			return LoginResult{}, fmt.Errorf("%w: bad currency %s", player.ErrValidation, cmd.Currency)
+		}
+		meta["currency"] = c
+	}
+
+	var res LoginResult
+	err = s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		_, err := s.players.GetByEmail(ctx, strings.ToLower(strings.TrimSpace(id.Email)))
+		if err == nil {
+			return fmt.Errorf("%w: email already registered", player.ErrConflict)
+		}
+		if !errors.Is(err, player.ErrNotFound) {
+			return err
+		}
+
+		p, err := s.registrar.CreatePlayer(ctx, playeruc.CreatePlayerCmd{
+			Email:          id.Email,
+			BirthDate:      cmd.BirthDate,
+			CountryCode:    cmd.CountryCode,
+			Locale:         cmd.Locale,
+			TimeZone:       cmd.TimeZone,
+			RegistrationIP: cmd.IP,
+			Metadata:       meta,
+			RegisteredAt:   s.clock.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		if id.EmailVerified {
+			if _, err := s.contacts.VerifyEmail(ctx, p.ID, p.Email); err != nil {
+				return err
+			}
+		}
+
+		a, err := auth.NewSocialAccount(p.ID, id, s.clock.Now())
+		if err != nil {
+			return err
+		}
+		if err := s.social.Create(ctx, a); err != nil {
+			return err
+		}
+
+		res, err = s.signIn(ctx, p.ID, auth.SocialMethod(id.Provider), cmd.IP, cmd.UserAgent)
+		if err != nil {
+			return err
+		}
+		res.Created = true
+		return nil
+	})
+	if err != nil {
+		return LoginResult{}, err
+	}
+	return res, nil
+}
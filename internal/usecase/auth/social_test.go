@@ -0,0 +1,67 @@
+package authuc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"players_service/internal/domain/auth"
+	"players_service/internal/domain/player"
+)
+
+func TestUnlinkKeepsLastLoginMethod(t *testing.T) {
+	e := newTestEnv(t, Config{})
+	ctx := context.Background()
+	p := e.addPlayer(t, "p@example.com", "")
+
+	for _, prov := range []string{"google", "telegram"} {
+		if _, err := e.svc.LinkSocialAccount(ctx, p.ID, prov, prov+"-1"); err != nil {
+			t.Fatalf("link %s: %v", prov, err)
+		}
+	}
+	if err := e.svc.UnlinkSocialAccount(ctx, p.ID, "google"); err != nil {
+		t.Fatalf("unlink with another account left: %v", err)
+	}
+	if err := e.svc.UnlinkSocialAccount(ctx, p.ID, "telegram"); !errors.Is(err, player.ErrForbidden) {
+		t.Fatalf("unlink the last method: want ErrForbidden, got %v", err)
+	}
+	if err := e.svc.UnlinkSocialAccount(ctx, p.ID, "google"); !errors.Is(err, player.ErrNotFound) {
+		t.Fatalf("unlink twice: want ErrNotFound, got %v", err)
+	}
+
+	hash, _ := e.hasher.Hash("a-password")
+	_ = e.passwords.Upsert(ctx, auth.NewPasswordCredential(p.ID, hash, e.clock.Now()))
+	if err := e.svc.UnlinkSocialAccount(ctx, p.ID, "telegram"); err != nil {
+		t.Fatalf("unlink with a password set: %v", err)
+	}
+}
+
+func TestParallelUnlinksKeepOneMethod(t *testing.T) {
+	e := newTestEnv(t, Config{})
+	ctx := context.Background()
+	p := e.addPlayer(t, "p@example.com", "")
+	for _, prov := range []string{"google", "telegram"} {
+		if _, err := e.svc.LinkSocialAccount(ctx, p.ID, prov, prov+"-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	e.social.deleteDelay = 20 * time.Millisecond
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for j, prov := range []string{"google", "telegram"} {
+		wg.Add(1)
+		go func(j int, prov string) {
+			defer wg.Done()
+			errs[j] = e.svc.UnlinkSocialAccount(ctx, p.ID, prov)
+		}(j, prov)
+	}
+	wg.Wait()
+
+	left, _ := e.social.ListByPlayer(ctx, p.ID)
+	if len(left) != 1 {
+		t.Fatalf("%d accounts left (errors %v), want exactly one", len(left), errs)
+	}
+}
@@ -0,0 +1,37 @@
+package outboxuc
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	playeruc "players_service/internal/usecase/player"
+)
+
+// Pending is an unpublished outbox message together with its delivery state.
+type Pending struct {
+	Message  playeruc.OutboxMessage
+	Attempts int
+}
+
+type Repository interface {
+	// Claim leases up to limit due messages until leaseUntil, oldest first.
+	// Only the oldest unpublished message of each key is due, so per-key
+	// order holds across relays. A leased message is not claimed again
+	// before the lease runs out.
+	Claim(ctx context.Context, now, leaseUntil time.Time, limit int) ([]Pending, error)
+	// MarkPublished and MarkFailed end the lease.
+	MarkPublished(ctx context.Context, ids []uuid.UUID, at time.Time) error
+	MarkFailed(ctx context.Context, id uuid.UUID, attempts int, lastErr string, nextAttemptAt time.Time) error
+}
+
+// Publisher delivers a message to the outside world (broker, webhook, log...).
+// A nil error means the message is delivered and will not be sent again.
+type Publisher interface {
+	Publish(ctx context.Context, msg playeruc.OutboxMessage) error
+}
+
+type Clock interface {
+	Now() time.Time
+}
@@ -0,0 +1,143 @@
+package outboxuc
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Config struct {
+	BatchSize      int
+	PollInterval   time.Duration
+	PublishTimeout time.Duration // per message
+	// Lease is how long a claimed batch belongs to this relay. Messages not
+	// published by then are left to the next claim.
+	Lease       time.Duration
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+}
+
+func (c Config) withDefaults() Config {
+	if c.BatchSize <= 0 {
+		c.BatchSize = 100
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = time.Second
+	}
+	if c.PublishTimeout <= 0 {
+		c.PublishTimeout = 10 * time.Second
+	}
+	if c.Lease <= 0 {
+		c.Lease = 5 * time.Minute
+	}
+	if c.Lease < 2*c.PublishTimeout {
+		c.Lease = 2 * c.PublishTimeout
+	}
+	if c.BackoffBase <= 0 {
+		c.BackoffBase = time.Second
+	}
+	if c.BackoffMax <= 0 {
+		c.BackoffMax = 10 * time.Minute
+	}
+	return c
+}
+
+// Relay moves messages from the outbox table to a Publisher.
+// Delivery is at-least-once: consumers must be idempotent on message id.
+type Relay struct {
+	repo  Repository
+	pub   Publisher
+	clock Clock
+	cfg   Config
+}
+
+func NewRelay(repo Repository, pub Publisher, clock Clock, cfg Config) *Relay {
+	return &Relay{
+		repo:  repo,
+		pub:   pub,
+		clock: clock,
+		cfg:   cfg.withDefaults(),
+	}
+}
+
+// Run polls the outbox until ctx is cancelled. A batch that is already being
+// published is finished before returning.
+func (r *Relay) Run(ctx context.Context) {
+	t := time.NewTicker(r.cfg.PollInterval)
+	defer t.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			n, err := r.RunOnce(context.WithoutCancel(ctx))
+			if err != nil {
+				log.Printf("outbox relay: %v", err)
+				break
+			}
+			if n < r.cfg.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// RunOnce claims and publishes one batch, returning how many messages were
+// claimed. The claim is a short statement of its own: no transaction or row
+// lock is held while publishing.
+func (r *Relay) RunOnce(ctx context.Context) (int, error) {
+	now := r.clock.Now()
+	leaseUntil := now.Add(r.cfg.Lease)
+	batch, err := r.repo.Claim(ctx, now, leaseUntil, r.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := make([]uuid.UUID, 0, len(batch))
+	for _, p := range batch {
+		// past the lease another relay may have taken the rest
+		if r.clock.Now().Add(r.cfg.PublishTimeout).After(leaseUntil) {
+			break
+		}
+
+		pctx, cancel := context.WithTimeout(ctx, r.cfg.PublishTimeout)
+		err := r.pub.Publish(pctx, p.Message)
+		cancel()
+
+		if err != nil {
+			attempts := p.Attempts + 1
+			next := r.clock.Now().Add(r.backoff(attempts))
+			log.Printf("outbox relay: publish %s (%s) attempt %d: %v", p.Message.ID, p.Message.Type, attempts, err)
+			if err := r.repo.MarkFailed(ctx, p.Message.ID, attempts, err.Error(), next); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		published = append(published, p.Message.ID)
+	}
+
+	if len(published) > 0 {
+		if err := r.repo.MarkPublished(ctx, published, r.clock.Now()); err != nil {
+			return 0, err
+		}
+	}
+	return len(batch), nil
+}
+
+// backoff is exponential in the number of failed attempts, capped at BackoffMax.
+func (r *Relay) backoff(attempts int) time.Duration {
+	d := r.cfg.BackoffBase
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= r.cfg.BackoffMax {
+			return r.cfg.BackoffMax
+		}
+	}
+	return d
+}
@@ -0,0 +1,177 @@
+package outboxuc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	playeruc "players_service/internal/usecase/player"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+type fakeRow struct {
+	msg         playeruc.OutboxMessage
+	attempts    int
+	nextAttempt time.Time
+	lockedUntil time.Time
+	published   bool
+}
+
+// fakeRepo claims like the postgres one: the oldest unpublished message of
+// each key, unless it is leased or in backoff.
+type fakeRepo struct {
+	mu   sync.Mutex
+	rows []*fakeRow
+}
+
+func (r *fakeRepo) add(key string, at time.Time) uuid.UUID {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := uuid.New()
+	r.rows = append(r.rows, &fakeRow{msg: playeruc.OutboxMessage{ID: id, Key: key, Type: "test", CreatedAt: at}})
+	return id
+}
+
+func (r *fakeRepo) Claim(_ context.Context, now, leaseUntil time.Time, limit int) ([]Pending, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Pending
+	seen := map[string]bool{}
+	for _, row := range r.rows {
+		if row.published {
+			continue
+		}
+		head := !seen[row.msg.Key]
+		seen[row.msg.Key] = true
+		if !head || row.nextAttempt.After(now) || row.lockedUntil.After(now) || len(out) == limit {
+			continue
+		}
+		row.lockedUntil = leaseUntil
+		out = append(out, Pending{Message: row.msg, Attempts: row.attempts})
+	}
+	return out, nil
+}
+
+func (r *fakeRepo) find(id uuid.UUID) *fakeRow {
+	for _, row := range r.rows {
+		if row.msg.ID == id {
+			return row
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepo) MarkPublished(_ context.Context, ids []uuid.UUID, _ time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		row := r.find(id)
+		row.published, row.lockedUntil = true, time.Time{}
+	}
+	return nil
+}
+
+func (r *fakeRepo) MarkFailed(_ context.Context, id uuid.UUID, attempts int, _ string, next time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	row := r.find(id)
+	row.attempts, row.nextAttempt, row.lockedUntil = attempts, next, time.Time{}
+	return nil
+}
+
+// fakePublisher fails the messages in failing once each.
+type fakePublisher struct {
+	clock   *fakeClock
+	took    time.Duration
+	failing map[uuid.UUID]bool
+	sent    []uuid.UUID
+}
+
+func (p *fakePublisher) Publish(_ context.Context, msg playeruc.OutboxMessage) error {
+	p.clock.Advance(p.took)
+	if p.failing[msg.ID] {
+		delete(p.failing, msg.ID)
+		return errors.New("broker down")
+	}
+	p.sent = append(p.sent, msg.ID)
+	return nil
+}
+
+func TestRelayKeepsKeyOrderAcrossFailures(t *testing.T) {
+	clk := &fakeClock{now: time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)}
+	repo := &fakeRepo{}
+	a1 := repo.add("a", clk.Now())
+	b1 := repo.add("b", clk.Now().Add(time.Millisecond))
+	a2 := repo.add("a", clk.Now().Add(2*time.Millisecond))
+	pub := &fakePublisher{clock: clk, failing: map[uuid.UUID]bool{a1: true}}
+	relay := NewRelay(repo, pub, clk, Config{BackoffBase: time.Minute})
+	ctx := context.Background()
+
+	if n, err := relay.RunOnce(ctx); err != nil || n != 2 {
+		t.Fatalf("first run: claimed %d, %v; want a1 and b1", n, err)
+	}
+	if n, _ := relay.RunOnce(ctx); n != 0 {
+		t.Fatalf("a2 claimed while a1 waits for its retry")
+	}
+
+	clk.Advance(time.Minute)
+	_, _ = relay.RunOnce(ctx)
+	_, _ = relay.RunOnce(ctx)
+
+	want := []uuid.UUID{b1, a1, a2}
+	if len(pub.sent) != len(want) {
+		t.Fatalf("sent %v, want %v", pub.sent, want)
+	}
+	for i := range want {
+		if pub.sent[i] != want[i] {
+			t.Fatalf("sent %v, want %v", pub.sent, want)
+		}
+	}
+}
+
+func TestRelayLeavesMessagesPastTheLease(t *testing.T) {
+	clk := &fakeClock{now: time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)}
+	repo := &fakeRepo{}
+	for _, key := range []string{"a", "b", "c"} {
+		repo.add(key, clk.Now())
+	}
+	pub := &fakePublisher{clock: clk, took: 3 * time.Second, failing: map[uuid.UUID]bool{}}
+	relay := NewRelay(repo, pub, clk, Config{PublishTimeout: 5 * time.Second, Lease: 10 * time.Second})
+	ctx := context.Background()
+
+	// a and b take 6s of the 10s lease, c could time out past it
+	if n, err := relay.RunOnce(ctx); err != nil || n != 3 {
+		t.Fatalf("claimed %d, %v", n, err)
+	}
+	if len(pub.sent) != 2 {
+		t.Fatalf("published %d messages, want the two that fit the lease", len(pub.sent))
+	}
+	if n, _ := relay.RunOnce(ctx); n != 0 {
+		t.Fatal("leased messages claimed again before the lease ran out")
+	}
+
+	clk.Advance(10 * time.Second)
+	if n, _ := relay.RunOnce(ctx); n != 1 || len(pub.sent) != 3 {
+		t.Fatalf("after the lease: claimed %d, published %d", n, len(pub.sent))
+	}
+}
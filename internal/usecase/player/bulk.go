@@ -0,0 +1,107 @@
+package playeruc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/player"
+)
+
+// Per-player outcomes of BulkChangeStatus.
+const (
+	BulkResultOK                = "ok"
+	BulkResultNotFound          = "not_found"
+	BulkResultConflict          = "conflict"
+	BulkResultInvalidTransition = "invalid_transition"
+	BulkResultError             = "error"
+)
+
+type BulkChangeStatusCmd struct {
+	PlayerIDs []uuid.UUID
+	ToStatus  string
+	Reason    string
+	Actor     player.ActorType
+	Until     time.Time
+}
+
+type BulkStatusResult struct {
+	PlayerID uuid.UUID
+	Result   string
+	Event    *player.PlayerStatusEvent // set when Result is ok
+	Detail   string                    // error text otherwise
+}
+
+// BulkChangeStatus applies the same status change to many players. Every player
+// is changed in its own transaction, so one failure does not roll back the rest.
+func (s *Service) BulkChangeStatus(ctx context.Context, cmd BulkChangeStatusCmd) ([]BulkStatusResult, error) {
+	if _, err := player.ParseStatus(strings.ToLower(strings.TrimSpace(cmd.ToStatus))); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(cmd.Reason) == "" {
+		return nil, fmt.Errorf("%w: status_reason required", player.ErrValidation)
+	}
+
+	ids := make([]uuid.UUID, 0, len(cmd.PlayerIDs))
+	seen := make(map[uuid.UUID]struct{}, len(cmd.PlayerIDs))
+	for _, id := range cmd.PlayerIDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: ids required", player.ErrValidation)
+	}
+	if len(ids) > MaxBatchSize {
+		return nil, fmt.Errorf("%w: at most %d ids per request", player.ErrValidation, MaxBatchSize)
+	}
+
+	results := make([]BulkStatusResult, 0, len(ids))
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		_, ev, err := s.ChangeStatus(ctx, ChangeStatusCmd{
+			PlayerID: id,
+			ToStatus: cmd.ToStatus,
+			Reason:   cmd.Reason,
+			Actor:    cmd.Actor,
+			Until:    cmd.Until,
+		})
+		if err != nil {
+			res := BulkStatusResult{PlayerID: id, Result: bulkResult(err), Detail: err.Error()}
+			if res.Result == BulkResultError {
+				// do not leak infrastructure errors to the caller
+				log.Printf("bulk status change %s: %v", id, err)
+				res.Detail = "internal error"
+			}
+			results = append(results, res)
+			continue
+		}
+		results = append(results, BulkStatusResult{PlayerID: id, Result: BulkResultOK, Event: &ev})
+	}
+	return results, nil
+}
+
+func bulkResult(err error) string {
+	switch {
+	case errors.Is(err, player.ErrNotFound):
+		return BulkResultNotFound
+	case errors.Is(err, player.ErrConflict):
+		return BulkResultConflict
+	case errors.Is(err, player.ErrForbidden),
+		errors.Is(err, player.ErrValidation),
+		errors.Is(err, player.ErrInvalidStatus):
+		return BulkResultInvalidTransition
+	default:
+		return BulkResultError
+	}
+}
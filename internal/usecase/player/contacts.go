@@ -0,0 +1,133 @@
+package playeruc
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/player"
+)
+
+// VerifyEmail marks email of the player as confirmed. The caller has already
+// checked the verification code; a changed email yields ErrConflict.
+func (s *Service) VerifyEmail(ctx context.Context, playerID uuid.UUID, email string) (*player.Player, error) {
+	return s.verifyContact(ctx, playerID, "player.email.verified", func(p *player.Player, now time.Time) (map[string]any, error) {
+		if err := p.MarkEmailVerified(email, now); err != nil {
+			return nil, err
+		}
+		return map[string]any{"email": p.Email}, nil
+	})
+}
+
+// VerifyPhone is VerifyEmail for the phone.
+func (s *Service) VerifyPhone(ctx context.Context, playerID uuid.UUID, phone string) (*player.Player, error) {
+	return s.verifyContact(ctx, playerID, "player.phone.verified", func(p *player.Player, now time.Time) (map[string]any, error) {
+		if err := p.MarkPhoneVerified(phone, now); err != nil {
+			return nil, err
+		}
+		return map[string]any{"phone": p.Phone}, nil
+	})
+}
+
+func (s *Service) verifyContact(
+	ctx context.Context,
+	playerID uuid.UUID,
+	eventType string,
+	mark func(p *player.Player, now time.Time) (map[string]any, error),
+) (*player.Player, error) {
+	now := s.clock.Now()
+
+	var updated *player.Player
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		p, err := s.players.GetByID(ctx, playerID)
+		if err != nil {
+			return err
+		}
+		payload, err := mark(p, now)
+		if err != nil {
+			return err
+		}
+		if err := s.players.Update(ctx, p); err != nil {
+			return err
+		}
+
+		if s.outbox != nil {
+			payload["player_id"] = p.ID.String()
+			payload["verified_at"] = now.Format(time.RFC3339Nano)
+			payload["version"] = p.Version
+
+			msg, err := NewOutboxMessage("player", p.ID, eventType, p.ID.String(), payload, now)
+			if err != nil {
+				return err
+			}
+			if err := s.outbox.Enqueue(ctx, msg); err != nil {
+				return err
+			}
+		}
+		updated = p
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// AddCredential adds an already confirmed email or phone to the player.
+func (s *Service) AddCredential(ctx context.Context, playerID uuid.UUID, kind player.CredentialKind, value string) (player.Credential, error) {
+	now := s.clock.Now()
+
+	var added player.Credential
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		p, err := s.players.GetByID(ctx, playerID)
+		if err != nil {
+			return err
+		}
+		c, err := p.AddCredential(kind, value, now, now)
+		if err != nil {
+			return err
+		}
+		if err := s.players.AddCredential(ctx, c); err != nil {
+			return err
+		}
+		if c.Primary {
+			if err := s.players.Update(ctx, p); err != nil {
+				return err
+			}
+		}
+
+		if s.outbox != nil {
+			msg, err := NewOutboxMessage(
+				"player",
+				p.ID,
+				"player.credential.added",
+				p.ID.String(),
+				map[string]any{
+					"player_id": p.ID.String(),
+					"kind":      c.Kind.String(),
+					"value":     c.Value,
+					"primary":   c.Primary,
+					"added_at":  now.Format(time.RFC3339Nano),
+				},
+				now,
+			)
+			if err != nil {
+				return err
+			}
+			if err := s.outbox.Enqueue(ctx, msg); err != nil {
+				return err
+			}
+		}
+		added = c
+		return nil
+	})
+	if err != nil {
+		return player.Credential{}, err
+	}
+	return added, nil
+}
+
+func (s *Service) ListCredentials(ctx context.Context, playerID uuid.UUID) ([]player.Credential, error) {
+	return s.players.ListCredentials(ctx, playerID)
+}
@@ -0,0 +1,241 @@
+package playeruc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/player"
+)
+
+// DocumentContentTypes are the file formats accepted for documents.
+var DocumentContentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+}
+
+// UploadDocumentFile stores a file of the player and returns its ref, which
+// the player then submits as a document. contentType is sniffed by the caller.
+func (s *Service) UploadDocumentFile(ctx context.Context, playerID uuid.UUID, contentType string, r io.Reader) (string, error) {
+	if !DocumentContentTypes[contentType] {
+		return "", fmt.Errorf("%w: unsupported file type %s", player.ErrValidation, contentType)
+	}
+	if _, err := s.players.GetByID(ctx, playerID); err != nil {
+		return "", err
+	}
+	return s.files.Save(ctx, playerID, contentType, r)
+}
+
+type SubmitDocumentCmd struct {
+	PlayerID uuid.UUID
+	Type     string
+	FileRef  string // from UploadDocumentFile
+}
+
+func (s *Service) SubmitDocument(ctx context.Context, cmd SubmitDocumentCmd) (*player.Document, error) {
+	now := s.clock.Now()
+
+	typ, err := player.ParseDocumentType(strings.ToLower(strings.TrimSpace(cmd.Type)))
+	if err != nil {
+		return nil, err
+	}
+	d, err := player.NewDocument(cmd.PlayerID, typ, strings.TrimSpace(cmd.FileRef), now)
+	if err != nil {
+		return nil, err
+	}
+	owned, err := s.files.Owned(ctx, cmd.PlayerID, d.FileRef)
+	if err != nil {
+		return nil, err
+	}
+	if !owned {
+		return nil, fmt.Errorf("%w: unknown file", player.ErrValidation)
+	}
+
+	err = s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.players.GetByID(ctx, cmd.PlayerID); err != nil {
+			return err
+		}
+		if err := s.docs.Create(ctx, d); err != nil {
+			return err
+		}
+
+		if s.outbox != nil {
+			msg, err := NewOutboxMessage(
+				"player",
+				d.PlayerID,
+				"player.document.submitted",
+				d.PlayerID.String(),
+				map[string]any{
+					"id":         d.ID.String(),
+					"player_id":  d.PlayerID.String(),
+					"type":       d.Type.String(),
+					"created_at": d.CreatedAt.Format(time.RFC3339Nano),
+				},
+				now,
+			)
+			if err != nil {
+				return err
+			}
+			if err := s.outbox.Enqueue(ctx, msg); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// ListDocuments returns documents of the player, newest first.
+func (s *Service) ListDocuments(ctx context.Context, playerID uuid.UUID) ([]*player.Document, error) {
+	// 404 for unknown players instead of an empty list
+	if _, err := s.players.GetByID(ctx, playerID); err != nil {
+		return nil, err
+	}
+	return s.docs.ListByPlayer(ctx, playerID)
+}
+
+type ReviewDocumentCmd struct {
+	DocumentID uuid.UUID
+	Status     string // approved|rejected
+	Reason     string // required for rejected
+	Actor      player.ActorType
+}
+
+func (s *Service) ReviewDocument(ctx context.Context, cmd ReviewDocumentCmd) (*player.Document, player.DocumentReview, error) {
+	now := s.clock.Now()
+
+	to, err := player.ParseDocumentStatus(strings.ToLower(strings.TrimSpace(cmd.Status)))
+	if err != nil {
+		return nil, player.DocumentReview{}, err
+	}
+
+	var (
+		updated *player.Document
+		review  player.DocumentReview
+	)
+	err = s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		d, err := s.docs.GetByID(ctx, cmd.DocumentID)
+		if err != nil {
+			return err
+		}
+		rv, err := d.Review(to, cmd.Reason, cmd.Actor, now)
+		if err != nil {
+			return err
+		}
+		if err := s.docs.Update(ctx, d); err != nil {
+			return err
+		}
+		if err := s.docs.AppendReview(ctx, rv); err != nil {
+			return err
+		}
+
+		if s.outbox != nil {
+			msg, err := NewOutboxMessage(
+				"player",
+				d.PlayerID,
+				"player.document.reviewed",
+				d.PlayerID.String(),
+				map[string]any{
+					"id":          rv.ID.String(),
+					"document_id": d.ID.String(),
+					"player_id":   d.PlayerID.String(),
+					"type":        d.Type.String(),
+					"from_status": rv.From.String(),
+					"to_status":   rv.To.String(),
+					"reason":      rv.Reason,
+					"actor_type":  rv.ActorType.String(),
+					"created_at":  rv.CreatedAt.Format(time.RFC3339Nano),
+				},
+				now,
+			)
+			if err != nil {
+				return err
+			}
+			if err := s.outbox.Enqueue(ctx, msg); err != nil {
+				return err
+			}
+		}
+		p, err := s.players.GetByID(ctx, d.PlayerID)
+		if err != nil {
+			return err
+		}
+		if err := s.recomputeKYCLevel(ctx, p, now); err != nil {
+			return err
+		}
+
+		updated = d
+		review = rv
+		return nil
+	})
+	if err != nil {
+		return nil, player.DocumentReview{}, err
+	}
+	return updated, review, nil
+}
+
+// OpenDocumentFile returns the file of a document with its content type.
+// The caller closes it.
+func (s *Service) OpenDocumentFile(ctx context.Context, documentID uuid.UUID) (io.ReadCloser, string, error) {
+	d, err := s.docs.GetByID(ctx, documentID)
+	if err != nil {
+		return nil, "", err
+	}
+	return s.files.Open(ctx, d.FileRef)
+}
+
+// recomputeKYCLevel derives the KYC level of p from its approved documents
+// under the rule of its country, and saves p when the level changes. Must be
+// called inside a transaction.
+func (s *Service) recomputeKYCLevel(ctx context.Context, p *player.Player, now time.Time) error {
+	docs, err := s.docs.ListByPlayer(ctx, p.ID)
+	if err != nil {
+		return err
+	}
+	var approved []player.DocumentType
+	for _, d := range docs {
+		if d.Status == player.DocumentStatusApproved {
+			approved = append(approved, d.Type)
+		}
+	}
+
+	from, changed := p.RecomputeKYCLevel(s.kycRules, approved, now)
+	if !changed {
+		return nil
+	}
+	if err := s.players.Update(ctx, p); err != nil {
+		return err
+	}
+
+	if s.outbox != nil {
+		msg, err := NewOutboxMessage(
+			"player",
+			p.ID,
+			"player.kyc_level.changed",
+			p.ID.String(),
+			map[string]any{
+				"player_id":    p.ID.String(),
+				"from_level":   from.String(),
+				"to_level":     p.KYCLevel.String(),
+				"country_code": p.Address.CountryCode,
+				"version":      p.Version,
+				"changed_at":   now.Format(time.RFC3339Nano),
+			},
+			now,
+		)
+		if err != nil {
+			return err
+		}
+		if err := s.outbox.Enqueue(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package playeruc
+
+import (
+	"time"
+
+	"players_service/internal/domain/player"
+)
+
+// Outbox payloads with a fixed schema. Fields may be added within a schema
+// version; renaming or removing one requires bumping the version.
+
+const PlayerCreatedSchemaVersion = 1
+
+type AddressPayload struct {
+	CountryCode string `json:"country_code"`
+	Locale      string `json:"locale"`
+	TimeZone    string `json:"time_zone"`
+}
+
+// PlayerCreatedV1 is the "player.created" payload, schema_version 1.
+type PlayerCreatedV1 struct {
+	SchemaVersion  int            `json:"schema_version"`
+	ID             string         `json:"id"`
+	Email          string         `json:"email"`
+	Phone          string         `json:"phone"`
+	FirstName      string         `json:"first_name"`
+	LastName       string         `json:"last_name"`
+	BirthDate      *string        `json:"birth_date"` // YYYY-MM-DD
+	Gender         string         `json:"gender"`
+	Status         string         `json:"status"`
+	Address        AddressPayload `json:"address"`
+	RegistrationIP *string        `json:"registration_ip"`
+	IPCountry      *string        `json:"registration_ip_country"`
+	RegisteredAt   *string        `json:"registered_at"` // RFC3339Nano
+	Metadata       map[string]any `json:"metadata"`
+	CreatedAt      string         `json:"created_at"`
+}
+
+func newPlayerCreatedV1(p *player.Player) PlayerCreatedV1 {
+	ev := PlayerCreatedV1{
+		SchemaVersion: PlayerCreatedSchemaVersion,
+		ID:            p.ID.String(),
+		Email:         p.Email,
+		Phone:         p.Phone,
+		FirstName:     p.FirstName,
+		LastName:      p.LastName,
+		Gender:        p.Gender.String(),
+		Status:        p.Status.String(),
+		Address: AddressPayload{
+			CountryCode: p.Address.CountryCode,
+			Locale:      p.Address.Locale,
+			TimeZone:    p.Address.TimeZone,
+		},
+		Metadata:  make(map[string]any, len(p.Metadata)),
+		CreatedAt: p.CreatedAt.Format(time.RFC3339Nano),
+	}
+	if !p.BirthDate.IsZero() {
+		d := p.BirthDate.Format("2006-01-02")
+		ev.BirthDate = &d
+	}
+	if len(p.RegistrationIP) > 0 {
+		ip := p.RegistrationIP.String()
+		ev.RegistrationIP = &ip
+	}
+	if p.RegistrationIPCountry != "" {
+		c := p.RegistrationIPCountry
+		ev.IPCountry = &c
+	}
+	if !p.RegisteredAt.IsZero() {
+		at := p.RegisteredAt.Format(time.RFC3339Nano)
+		ev.RegisteredAt = &at
+	}
+	for k, v := range p.Metadata {
+		ev.Metadata[k] = v
+	}
+	return ev
+}
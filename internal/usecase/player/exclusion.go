@@ -0,0 +1,77 @@
+package playeruc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/player"
+)
+
+// SelfExclude blocks the player, on their own request, for the period of kind
+// (24h|6_months|permanent). It is audited and published as a regular status
+// change, plus a "player.self_excluded" message for wallets and games.
+func (s *Service) SelfExclude(ctx context.Context, playerID uuid.UUID, kind string) (*player.Player, player.PlayerStatusEvent, error) {
+	now := s.clock.Now()
+
+	k, err := player.ParseExclusionKind(strings.ToLower(strings.TrimSpace(kind)))
+	if err != nil {
+		return nil, player.PlayerStatusEvent{}, err
+	}
+
+	var (
+		updated *player.Player
+		ev      player.PlayerStatusEvent
+	)
+	err = s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		p, err := s.players.GetByID(ctx, playerID)
+		if err != nil {
+			return err
+		}
+		event, err := p.SelfExclude(k, now)
+		if err != nil {
+			return err
+		}
+		if err := s.saveStatusChange(ctx, p, event, now); err != nil {
+			return err
+		}
+
+		if s.outbox != nil {
+			var until any
+			if !p.ExcludedUntil.IsZero() {
+				until = p.ExcludedUntil.Format(time.RFC3339Nano)
+			}
+			msg, err := NewOutboxMessage(
+				"player",
+				p.ID,
+				"player.self_excluded",
+				p.ID.String(),
+				map[string]any{
+					"player_id":  p.ID.String(),
+					"kind":       p.ExclusionKind.String(),
+					"until":      until,
+					"event_id":   event.ID.String(),
+					"version":    p.Version,
+					"created_at": now.Format(time.RFC3339Nano),
+				},
+				now,
+			)
+			if err != nil {
+				return err
+			}
+			if err := s.outbox.Enqueue(ctx, msg); err != nil {
+				return err
+			}
+		}
+
+		updated = p
+		ev = event
+		return nil
+	})
+	if err != nil {
+		return nil, player.PlayerStatusEvent{}, err
+	}
+	return updated, ev, nil
+}
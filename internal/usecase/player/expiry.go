@@ -0,0 +1,108 @@
+package playeruc
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/player"
+)
+
+const statusExpiredReason = "temporary status expired"
+
+// ExpireStatuses reverts up to limit players whose temporary block/freeze is over
+// back to active. Each player is handled in its own transaction through the
+// regular status change path, so it gets an audit event and an outbox message.
+func (s *Service) ExpireStatuses(ctx context.Context, limit int) (int, error) {
+	ids, err := s.players.ListStatusExpired(ctx, s.clock.Now(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, id := range ids {
+		ok, err := s.expireStatus(ctx, id)
+		if err != nil {
+			// lost a race with another change; the next run will look again
+			if errors.Is(err, player.ErrConflict) || errors.Is(err, player.ErrNotFound) {
+				continue
+			}
+			return n, err
+		}
+		if ok {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (s *Service) expireStatus(ctx context.Context, id uuid.UUID) (bool, error) {
+	now := s.clock.Now()
+	reverted := false
+
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		p, err := s.players.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !p.StatusExpired(now) {
+			return nil
+		}
+
+		event, err := p.ChangeStatus(s.transitions, player.StatusActive, statusExpiredReason, player.ActorSystem, now)
+		if err != nil {
+			return err
+		}
+		if err := s.saveStatusChange(ctx, p, event, now); err != nil {
+			return err
+		}
+		reverted = true
+		return nil
+	})
+	return reverted, err
+}
+
+// StatusExpiryWorker periodically calls Service.ExpireStatuses.
+type StatusExpiryWorker struct {
+	svc      *Service
+	interval time.Duration
+	batch    int
+}
+
+func NewStatusExpiryWorker(svc *Service, interval time.Duration, batch int) *StatusExpiryWorker {
+	return &StatusExpiryWorker{svc: svc, interval: interval, batch: batch}
+}
+
+// Run blocks until ctx is cancelled.
+func (w *StatusExpiryWorker) Run(ctx context.Context) {
+	t := time.NewTicker(w.interval)
+	defer t.Stop()
+
+	for {
+		// drain everything that is due before sleeping again
+		for {
+			n, err := w.svc.ExpireStatuses(ctx, w.batch)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("status expiry: %v", err)
+				}
+				break
+			}
+			if n > 0 {
+				log.Printf("status expiry: reverted %d player(s)", n)
+			}
+			if n < w.batch {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package playeruc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/player"
+)
+
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 1000
+	MaxBatchSize     = 1000
+)
+
+var playerSortFields = map[string]string{
+	"created_at":    player.SortCreatedAt,
+	"createdat":     player.SortCreatedAt,
+	"registered_at": player.SortRegisteredAt,
+	"registeredat":  player.SortRegisteredAt,
+	"last_login_at": player.SortLastLoginAt,
+	"lastloginat":   player.SortLastLoginAt,
+	"email":         player.SortEmail,
+	"status":        player.SortStatus,
+	"country":       player.SortCountry,
+	"country_code":  player.SortCountry,
+}
+
+type ListPlayersQuery struct {
+	Offset   int
+	Limit    int // 0 means DefaultListLimit
+	Search   string
+	Country  string
+	Currency string
+	SortBy   string // see playerSortFields, default created_at
+	Order    string // asc|desc, default desc
+
+	EmailVerified *bool // nil means any
+	PhoneVerified *bool
+	KYCLevel      string // none|basic|full, optional
+}
+
+type PlayerList struct {
+	Items []*player.Player
+	Total int
+}
+
+func (s *Service) ListPlayers(ctx context.Context, q ListPlayersQuery) (PlayerList, error) {
+	f, err := q.filter()
+	if err != nil {
+		return PlayerList{}, err
+	}
+
+	items, total, err := s.players.List(ctx, f)
+	if err != nil {
+		return PlayerList{}, err
+	}
+	return PlayerList{Items: items, Total: total}, nil
+}
+
+func (q ListPlayersQuery) filter() (player.ListFilter, error) {
+	f := player.ListFilter{
+		Offset:   q.Offset,
+		Limit:    q.Limit,
+		Search:   strings.TrimSpace(q.Search),
+		Country:  strings.ToUpper(strings.TrimSpace(q.Country)),
+		Currency: strings.ToUpper(strings.TrimSpace(q.Currency)),
+		SortBy:   player.SortCreatedAt,
+		Desc:     true,
+
+		EmailVerified: q.EmailVerified,
+		PhoneVerified: q.PhoneVerified,
+	}
+
+	if f.Offset < 0 {
+		return player.ListFilter{}, fmt.Errorf("%w: offset must be >= 0", player.ErrValidation)
+	}
+	if f.Limit == 0 {
+		f.Limit = DefaultListLimit
+	}
+	if f.Limit < 1 || f.Limit > MaxListLimit {
+		return player.ListFilter{}, fmt.Errorf("%w: limit must be in [1, %d]", player.ErrValidation, MaxListLimit)
+	}
+	if f.Country != "" {
+		if err := (player.Address{CountryCode: f.Country}).Validate(); err != nil {
+			return player.ListFilter{}, err
+		}
+	}
+	if v := strings.ToLower(strings.TrimSpace(q.KYCLevel)); v != "" {
+		level, err := player.ParseKYCLevel(v)
+		if err != nil {
+			return player.ListFilter{}, err
+		}
+		f.KYCLevel = &level
+	}
+
+	if sortBy := strings.ToLower(strings.TrimSpace(q.SortBy)); sortBy != "" {
+		field, ok := playerSortFields[sortBy]
+		if !ok {
+			return player.ListFilter{}, fmt.Errorf("%w: unsupported sortBy %s", player.ErrValidation, q.SortBy)
+		}
+		f.SortBy = field
+	}
+
+	switch strings.ToLower(strings.TrimSpace(q.Order)) {
+	case "", "desc":
+		f.Desc = true
+	case "asc":
+		f.Desc = false
+	default:
+		return player.ListFilter{}, fmt.Errorf("%w: order must be asc or desc", player.ErrValidation)
+	}
+
+	return f, nil
+}
+
+type PlayerBatch struct {
+	Items    []*player.Player // in request order, duplicates collapsed
+	NotFound []uuid.UUID
+}
+
+func (s *Service) GetPlayers(ctx context.Context, ids []uuid.UUID) (PlayerBatch, error) {
+	uniq := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		uniq = append(uniq, id)
+	}
+	if len(uniq) > MaxBatchSize {
+		return PlayerBatch{}, fmt.Errorf("%w: at most %d ids per request", player.ErrValidation, MaxBatchSize)
+	}
+
+	found, err := s.players.GetByIDs(ctx, uniq)
+	if err != nil {
+		return PlayerBatch{}, err
+	}
+
+	byID := make(map[uuid.UUID]*player.Player, len(found))
+	for _, p := range found {
+		byID[p.ID] = p
+	}
+
+	res := PlayerBatch{
+		Items:    make([]*player.Player, 0, len(found)),
+		NotFound: []uuid.UUID{},
+	}
+	for _, id := range uniq {
+		if p, ok := byID[id]; ok {
+			res.Items = append(res.Items, p)
+		} else {
+			res.NotFound = append(res.NotFound, id)
+		}
+	}
+	return res, nil
+}
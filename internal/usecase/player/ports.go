@@ -2,6 +2,9 @@ package playeruc
 
 import (
 	"context"
+	"io"
+	"net"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -10,19 +13,58 @@ import (
 
 type PlayerRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*player.Player, error)
+	// GetByIDs returns found players only, in no particular order.
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*player.Player, error)
 	GetByEmail(ctx context.Context, email string) (*player.Player, error)
 	Create(ctx context.Context, p *player.Player) error
 	Update(ctx context.Context, p *player.Player) error
+	List(ctx context.Context, f player.ListFilter) ([]*player.Player, int, error)
+	ListStatusExpired(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error)
+
+	// AddCredential returns ErrConflict when the value belongs to any player.
+	AddCredential(ctx context.Context, c player.Credential) error
+	ListCredentials(ctx context.Context, playerID uuid.UUID) ([]player.Credential, error)
 }
 
 type PlayerStatusEventRepository interface {
 	Append(ctx context.Context, ev player.PlayerStatusEvent) error
+	// List returns events newest first, strictly after f.After when it is set.
+	List(ctx context.Context, f player.StatusEventFilter) ([]player.PlayerStatusEvent, error)
+}
+
+type DocumentRepository interface {
+	Create(ctx context.Context, d *player.Document) error
+	GetByID(ctx context.Context, id uuid.UUID) (*player.Document, error)
+	// Update returns ErrConflict when the document was changed concurrently.
+	Update(ctx context.Context, d *player.Document) error
+	ListByPlayer(ctx context.Context, playerID uuid.UUID) ([]*player.Document, error)
+	AppendReview(ctx context.Context, rv player.DocumentReview) error
+}
+
+// DocumentStorage keeps the files of documents. The refs it hands out are
+// stored on documents as they are.
+type DocumentStorage interface {
+	Save(ctx context.Context, playerID uuid.UUID, contentType string, r io.Reader) (string, error)
+	// Owned reports whether ref is an existing file saved for playerID.
+	Owned(ctx context.Context, playerID uuid.UUID, ref string) (bool, error)
+	// Open returns ErrNotFound for unknown refs.
+	Open(ctx context.Context, ref string) (io.ReadCloser, string, error)
 }
 
 type OutboxRepository interface {
 	Enqueue(ctx context.Context, msg OutboxMessage) error
 }
 
+// SessionRevoker ends all sessions of a player, within the caller's transaction.
+type SessionRevoker interface {
+	RevokeAllForPlayer(ctx context.Context, playerID uuid.UUID, at time.Time, reason string) (int, error)
+}
+
+// GeoLocator resolves an IP to an ISO country code, empty when unknown.
+type GeoLocator interface {
+	Country(ip net.IP) (string, error)
+}
+
 // UnitOfWork defines transaction boundary (TBD): one usecase == one transaction.
 type UnitOfWork interface {
 	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
@@ -0,0 +1,152 @@
+package playeruc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/player"
+)
+
+// UpdateProfileCmd is a partial update, nil fields are left untouched.
+// ExpectedVersion is the version the client has seen, a stale one yields ErrConflict.
+type UpdateProfileCmd struct {
+	PlayerID        uuid.UUID
+	ExpectedVersion int64
+
+	FirstName   *string
+	LastName    *string
+	Phone       *string
+	BirthDate   *time.Time // pointer to zero time clears it
+	Gender      *string
+	CountryCode *string
+	Locale      *string
+	TimeZone    *string
+}
+
+func (s *Service) UpdateProfile(ctx context.Context, cmd UpdateProfileCmd) (*player.Player, error) {
+	now := s.clock.Now()
+
+	if cmd.ExpectedVersion <= 0 {
+		return nil, fmt.Errorf("%w: version required", player.ErrValidation)
+	}
+
+	upd := player.ProfileUpdate{
+		FirstName: cmd.FirstName,
+		LastName:  cmd.LastName,
+		Phone:     cmd.Phone,
+		BirthDate: cmd.BirthDate,
+	}
+	if cmd.Gender != nil {
+		g, err := player.ParseGender(strings.ToLower(strings.TrimSpace(*cmd.Gender)))
+		if err != nil {
+			return nil, err
+		}
+		upd.Gender = &g
+	}
+
+	var updated *player.Player
+
+	err := s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		p, err := s.players.GetByID(ctx, cmd.PlayerID)
+		if err != nil {
+			return err
+		}
+		if p.Version != cmd.ExpectedVersion {
+			return fmt.Errorf("%w: version mismatch", player.ErrConflict)
+		}
+
+		if cmd.CountryCode != nil || cmd.Locale != nil || cmd.TimeZone != nil {
+			addr := p.Address
+			if cmd.CountryCode != nil {
+				addr.CountryCode = strings.ToUpper(strings.TrimSpace(*cmd.CountryCode))
+			}
+			if cmd.Locale != nil {
+				addr.Locale = strings.TrimSpace(*cmd.Locale)
+			}
+			if cmd.TimeZone != nil {
+				addr.TimeZone = strings.TrimSpace(*cmd.TimeZone)
+			}
+			upd.Address = &addr
+		}
+
+		country := p.Address.CountryCode
+		changed, err := p.UpdateProfile(upd, s.policy, now)
+		if err != nil {
+			return err
+		}
+		updated = p
+		if len(changed) == 0 {
+			return nil
+		}
+
+		if err := s.players.Update(ctx, p); err != nil {
+			return err
+		}
+
+		if s.outbox != nil {
+			msg, err := NewOutboxMessage(
+				"player",
+				p.ID,
+				"player.profile.updated",
+				p.ID.String(),
+				map[string]any{
+					"player_id":  p.ID.String(),
+					"changed":    profileFields(p, changed),
+					"version":    p.Version,
+					"updated_at": p.UpdatedAt.Format(time.RFC3339Nano),
+				},
+				now,
+			)
+			if err != nil {
+				return err
+			}
+			if err := s.outbox.Enqueue(ctx, msg); err != nil {
+				return err
+			}
+		}
+
+		// the same documents may give another level under the new country's rule
+		if p.Address.CountryCode != country {
+			return s.recomputeKYCLevel(ctx, p, now)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// profileFields returns new values of the changed profile fields.
+func profileFields(p *player.Player, changed []string) map[string]any {
+	out := make(map[string]any, len(changed))
+	for _, f := range changed {
+		switch f {
+		case "first_name":
+			out[f] = p.FirstName
+		case "last_name":
+			out[f] = p.LastName
+		case "phone":
+			out[f] = p.Phone
+		case "birth_date":
+			if p.BirthDate.IsZero() {
+				out[f] = nil
+			} else {
+				out[f] = p.BirthDate.Format("2006-01-02")
+			}
+		case "gender":
+			out[f] = p.Gender.String()
+		case "address":
+			out[f] = map[string]any{
+				"country_code": p.Address.CountryCode,
+				"locale":       p.Address.Locale,
+				"time_zone":    p.Address.TimeZone,
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,116 @@
+package playeruc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/player"
+)
+
+type fakeUoW struct{}
+
+func (fakeUoW) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeClock struct{ now time.Time }
+
+func (c fakeClock) Now() time.Time { return c.now }
+
+// fakePlayers implements what the profile usecases use, the rest panics.
+type fakePlayers struct {
+	PlayerRepository
+	players map[uuid.UUID]player.Player
+}
+
+func (r *fakePlayers) GetByID(_ context.Context, id uuid.UUID) (*player.Player, error) {
+	p, ok := r.players[id]
+	if !ok {
+		return nil, player.ErrNotFound
+	}
+	return &p, nil
+}
+
+func (r *fakePlayers) Update(_ context.Context, p *player.Player) error {
+	if r.players[p.ID].Version != p.Version-1 {
+		return player.ErrConflict
+	}
+	r.players[p.ID] = *p
+	return nil
+}
+
+type fakeDocs struct {
+	DocumentRepository
+	docs []*player.Document
+}
+
+func (r *fakeDocs) ListByPlayer(_ context.Context, playerID uuid.UUID) ([]*player.Document, error) {
+	var out []*player.Document
+	for _, d := range r.docs {
+		if d.PlayerID == playerID {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+type fakeOutbox struct{ types []string }
+
+func (r *fakeOutbox) Enqueue(_ context.Context, msg OutboxMessage) error {
+	r.types = append(r.types, msg.Type)
+	return nil
+}
+
+func TestUpdateProfileCountryRecomputesKYCLevel(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	rules, err := player.ParseKYCRules("GB:basic=passport;full=proof_of_address")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := player.Player{
+		ID:        uuid.New(),
+		Email:     "p@example.com",
+		Status:    player.StatusActive,
+		BirthDate: time.Date(1990, 5, 1, 0, 0, 0, 0, time.UTC),
+		Address:   player.Address{CountryCode: "FR"},
+		KYCLevel:  player.KYCBasic,
+		Version:   3,
+	}
+	players := &fakePlayers{players: map[uuid.UUID]player.Player{p.ID: p}}
+	docs := &fakeDocs{}
+	for _, typ := range []player.DocumentType{player.DocumentPassport, player.DocumentProofOfAddress} {
+		docs.docs = append(docs.docs, &player.Document{ID: uuid.New(), PlayerID: p.ID, Type: typ, Status: player.DocumentStatusApproved})
+	}
+	outbox := &fakeOutbox{}
+	svc := New(fakeUoW{}, players, nil, docs, nil, outbox, fakeClock{now}, WithKYCRules(rules))
+	ctx := context.Background()
+
+	locale := "fr-FR"
+	got, err := svc.UpdateProfile(ctx, UpdateProfileCmd{PlayerID: p.ID, ExpectedVersion: 3, Locale: &locale})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.KYCLevel != player.KYCBasic || len(outbox.types) != 1 {
+		t.Fatalf("country unchanged: level %s, events %v", got.KYCLevel.String(), outbox.types)
+	}
+
+	// proof of address is enough for full in GB
+	country := "gb"
+	got, err = svc.UpdateProfile(ctx, UpdateProfileCmd{PlayerID: p.ID, ExpectedVersion: got.Version, CountryCode: &country})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.KYCLevel != player.KYCFull {
+		t.Fatalf("level %s after the move to GB, want full", got.KYCLevel.String())
+	}
+	if stored := players.players[p.ID]; stored.KYCLevel != player.KYCFull || stored.Version != got.Version {
+		t.Fatalf("stored level %s version %d, returned version %d", stored.KYCLevel.String(), stored.Version, got.Version)
+	}
+	want := []string{"player.profile.updated", "player.profile.updated", "player.kyc_level.changed"}
+	if len(outbox.types) != len(want) || outbox.types[2] != want[2] {
+		t.Fatalf("events %v, want %v", outbox.types, want)
+	}
+}
@@ -3,35 +3,94 @@ package playeruc
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
+	"players_service/internal/domain/auth"
 	"players_service/internal/domain/player"
 )
 
 type Service struct {
-	uow     UnitOfWork
-	players PlayerRepository
-	events  PlayerStatusEventRepository
-	outbox  OutboxRepository // optional, can be nil
-	clock   ClockReal
+	uow         UnitOfWork
+	players     PlayerRepository
+	events      PlayerStatusEventRepository
+	docs        DocumentRepository
+	files       DocumentStorage
+	outbox      OutboxRepository // optional, can be nil
+	clock       ClockReal
+	sessions    SessionRevoker // optional, can be nil
+	geo         GeoLocator     // optional, can be nil
+	transitions player.TransitionRules
+	kycRules    player.KYCRules
+	policy      player.JurisdictionPolicy
 }
 
 type ClockReal interface {
 	Now() time.Time
 }
 
-func New(uow UnitOfWork, players PlayerRepository, events PlayerStatusEventRepository, outbox OutboxRepository, clock ClockReal) *Service {
-	return &Service{
-		uow:     uow,
-		players: players,
-		events:  events,
-		outbox:  outbox,
-		clock:   clock,
+// Option configures optional collaborators of Service.
+type Option func(*Service)
+
+// WithSessionRevoker makes status changes into blocked/closed end the player's sessions.
+func WithSessionRevoker(r SessionRevoker) Option {
+	return func(s *Service) { s.sessions = r }
+}
+
+// WithTransitionRules replaces player.DefaultTransitionRules as the status
+// transition matrix.
+func WithTransitionRules(r player.TransitionRules) Option {
+	return func(s *Service) { s.transitions = r }
+}
+
+// WithKYCRules replaces player.DefaultKYCRules as the documents each KYC
+// level needs.
+func WithKYCRules(r player.KYCRules) Option {
+	return func(s *Service) { s.kycRules = r }
+}
+
+// WithJurisdictionPolicy replaces player.DefaultJurisdictionPolicy as the
+// age and country rules for registrations and profile updates.
+func WithJurisdictionPolicy(j player.JurisdictionPolicy) Option {
+	return func(s *Service) { s.policy = j }
+}
+
+// WithGeoLocator makes new players geolocated by registration IP, so that
+// restricted countries are caught whatever country the player declares.
+func WithGeoLocator(g GeoLocator) Option {
+	return func(s *Service) { s.geo = g }
+}
+
+func New(
+	uow UnitOfWork,
+	players PlayerRepository,
+	events PlayerStatusEventRepository,
+	docs DocumentRepository,
+	files DocumentStorage,
+	outbox OutboxRepository,
+	clock ClockReal,
+	opts ...Option,
+) *Service {
+	s := &Service{
+		uow:         uow,
+		players:     players,
+		events:      events,
+		docs:        docs,
+		files:       files,
+		outbox:      outbox,
+		clock:       clock,
+		transitions: player.DefaultTransitionRules(),
+		kycRules:    player.DefaultKYCRules(),
+		policy:      player.DefaultJurisdictionPolicy(),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 type CreatePlayerCmd struct {
@@ -70,18 +129,27 @@ func (s *Service) CreatePlayer(ctx context.Context, cmd CreatePlayerCmd) (*playe
 		}
 	}
 
+	var ipCountry string
+	if ip != nil && s.geo != nil {
+		ipCountry, err = s.geo.Country(ip)
+		if err != nil {
+			return nil, fmt.Errorf("geolocate registration ip: %w", err)
+		}
+	}
+
 	p, err := player.NewPlayer(player.CreateParams{
-		Email:          cmd.Email,
-		Phone:          cmd.Phone,
-		FirstName:      cmd.FirstName,
-		LastName:       cmd.LastName,
-		BirthDate:      cmd.BirthDate,
-		Gender:         g,
-		Address:        addr,
-		RegistrationIP: ip,
-		Metadata:       cmd.Metadata,
-		RegisteredAt:   cmd.RegisteredAt,
-	}, now)
+		Email:                 cmd.Email,
+		Phone:                 cmd.Phone,
+		FirstName:             cmd.FirstName,
+		LastName:              cmd.LastName,
+		BirthDate:             cmd.BirthDate,
+		Gender:                g,
+		Address:               addr,
+		RegistrationIP:        ip,
+		RegistrationIPCountry: ipCountry,
+		Metadata:              cmd.Metadata,
+		RegisteredAt:          cmd.RegisteredAt,
+	}, s.policy, now)
 	if err != nil {
 		return nil, err
 	}
@@ -94,7 +162,27 @@ func (s *Service) CreatePlayer(ctx context.Context, cmd CreatePlayerCmd) (*playe
 		if ex != nil {
 			return player.ErrConflict
 		}
-		return s.players.Create(ctx, p)
+		if err := s.players.Create(ctx, p); err != nil {
+			return err
+		}
+
+		if s.outbox != nil {
+			msg, err := NewOutboxMessage(
+				"player",
+				p.ID,
+				"player.created",
+				p.ID.String(),
+				newPlayerCreatedV1(p),
+				now,
+			)
+			if err != nil {
+				return err
+			}
+			if err := s.outbox.Enqueue(ctx, msg); err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 	if err != nil {
 		return nil, err
@@ -107,6 +195,7 @@ type ChangeStatusCmd struct {
 	ToStatus string
 	Reason   string
 	Actor    player.ActorType
+	Until    time.Time // optional, makes blocked/frozen temporary
 }
 
 func (s *Service) ChangeStatus(ctx context.Context, cmd ChangeStatusCmd) (*player.Player, player.PlayerStatusEvent, error) {
@@ -126,44 +215,15 @@ func (s *Service) ChangeStatus(ctx context.Context, cmd ChangeStatusCmd) (*playe
 			return err
 		}
 
-		event, err := p.ChangeStatus(to, cmd.Reason, cmd.Actor, now)
+		event, err := p.ChangeStatusUntil(s.transitions, to, cmd.Reason, cmd.Actor, cmd.Until, now)
 		if err != nil {
 			return err
 		}
 
-		if err := s.players.Update(ctx, p); err != nil {
-			return err
-		}
-		if err := s.events.Append(ctx, event); err != nil {
+		if err := s.saveStatusChange(ctx, p, event, now); err != nil {
 			return err
 		}
 
-		// Outbox pattern (optional) — enqueue message in the same tx.
-		if s.outbox != nil {
-			msg, err := NewOutboxMessage(
-				"player",
-				p.ID,
-				"player.status.changed",
-				p.ID.String(),
-				map[string]any{
-					"id":          event.ID.String(),
-					"player_id":   event.PlayerID.String(),
-					"from_status": event.From.String(),
-					"to_status":   event.To.String(),
-					"reason":      event.Reason,
-					"actor_type":  event.ActorType.String(),
-					"created_at":  event.CreatedAt.Format(time.RFC3339Nano),
-				},
-				now,
-			)
-			if err != nil {
-				return err
-			}
-			if err := s.outbox.Enqueue(ctx, msg); err != nil {
-				return err
-			}
-		}
-
 		updated = p
 		ev = event
 		return nil
@@ -175,6 +235,54 @@ func (s *Service) ChangeStatus(ctx context.Context, cmd ChangeStatusCmd) (*playe
 	return updated, ev, nil
 }
 
+// saveStatusChange persists a status change made on p: player row, audit event
+// and outbox message. Must be called inside a transaction.
+func (s *Service) saveStatusChange(ctx context.Context, p *player.Player, event player.PlayerStatusEvent, now time.Time) error {
+	if err := s.players.Update(ctx, p); err != nil {
+		return err
+	}
+	if err := s.events.Append(ctx, event); err != nil {
+		return err
+	}
+	if s.sessions != nil && (event.To == player.StatusBlocked || event.To == player.StatusClosed) {
+		if _, err := s.sessions.RevokeAllForPlayer(ctx, p.ID, now, auth.RevokeStatus); err != nil {
+			return err
+		}
+	}
+
+	// Outbox pattern (optional) — enqueue message in the same tx.
+	if s.outbox != nil {
+		var until any
+		if !event.Until.IsZero() {
+			until = event.Until.Format(time.RFC3339Nano)
+		}
+		msg, err := NewOutboxMessage(
+			"player",
+			p.ID,
+			"player.status.changed",
+			p.ID.String(),
+			map[string]any{
+				"id":          event.ID.String(),
+				"player_id":   event.PlayerID.String(),
+				"from_status": event.From.String(),
+				"to_status":   event.To.String(),
+				"reason":      event.Reason,
+				"actor_type":  event.ActorType.String(),
+				"until":       until,
+				"created_at":  event.CreatedAt.Format(time.RFC3339Nano),
+			},
+			now,
+		)
+		if err != nil {
+			return err
+		}
+		if err := s.outbox.Enqueue(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *Service) GetPlayer(ctx context.Context, id uuid.UUID) (*player.Player, error) {
 	return s.players.GetByID(ctx, id)
 }
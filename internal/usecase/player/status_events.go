@@ -0,0 +1,112 @@
+package playeruc
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/player"
+)
+
+const (
+	DefaultEventsLimit = 50
+	MaxEventsLimit     = 500
+)
+
+type ListStatusEventsQuery struct {
+	PlayerID  uuid.UUID
+	Cursor    string // opaque, from StatusEventPage.NextCursor
+	Limit     int    // 0 means DefaultEventsLimit
+	ActorType string // player|administrator|system, optional
+	ToStatus  string // active|blocked|frozen|closed, optional
+}
+
+type StatusEventPage struct {
+	Items      []player.PlayerStatusEvent
+	NextCursor string // empty when there are no more events
+}
+
+func (s *Service) ListStatusEvents(ctx context.Context, q ListStatusEventsQuery) (StatusEventPage, error) {
+	f := player.StatusEventFilter{
+		PlayerID: q.PlayerID,
+		Limit:    q.Limit,
+	}
+	if f.Limit == 0 {
+		f.Limit = DefaultEventsLimit
+	}
+	if f.Limit < 1 || f.Limit > MaxEventsLimit {
+		return StatusEventPage{}, fmt.Errorf("%w: limit must be in [1, %d]", player.ErrValidation, MaxEventsLimit)
+	}
+	if v := strings.ToLower(strings.TrimSpace(q.ActorType)); v != "" {
+		a, err := player.ParseActorType(v)
+		if err != nil {
+			return StatusEventPage{}, err
+		}
+		f.ActorType = a
+	}
+	if v := strings.ToLower(strings.TrimSpace(q.ToStatus)); v != "" {
+		st, err := player.ParseStatus(v)
+		if err != nil {
+			return StatusEventPage{}, err
+		}
+		f.ToStatus = st
+	}
+	if q.Cursor != "" {
+		c, err := decodeEventCursor(q.Cursor)
+		if err != nil {
+			return StatusEventPage{}, err
+		}
+		f.After = &c
+	}
+
+	// 404 for unknown players instead of an empty page
+	if _, err := s.players.GetByID(ctx, q.PlayerID); err != nil {
+		return StatusEventPage{}, err
+	}
+
+	want := f.Limit
+	f.Limit++ // one extra row tells whether there is a next page
+	items, err := s.events.List(ctx, f)
+	if err != nil {
+		return StatusEventPage{}, err
+	}
+
+	page := StatusEventPage{Items: items}
+	if len(items) > want {
+		page.Items = items[:want]
+		last := page.Items[want-1]
+		page.NextCursor = encodeEventCursor(player.StatusEventCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	return page, nil
+}
+
+func encodeEventCursor(c player.StatusEventCursor) string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeEventCursor(s string) (player.StatusEventCursor, error) {
+	bad := fmt.Errorf("%w: bad cursor", player.ErrValidation)
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return player.StatusEventCursor{}, bad
+	}
+	at, idStr, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return player.StatusEventCursor{}, bad
+	}
+	t, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return player.StatusEventCursor{}, bad
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return player.StatusEventCursor{}, bad
+	}
+	return player.StatusEventCursor{CreatedAt: t, ID: id}, nil
+}
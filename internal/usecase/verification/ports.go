@@ -0,0 +1,47 @@
+package verificationuc
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/verification"
+)
+
+type CodeRepository interface {
+	Create(ctx context.Context, c verification.Code) error
+	// Latest returns the newest unconsumed code for purpose and destination.
+	Latest(ctx context.Context, purpose verification.Purpose, dst verification.Destination) (verification.Code, error)
+	// IncrementAttempt counts a guess on an unconsumed code in one statement
+	// and returns the new count, ErrTooManyAttempts when none are left.
+	IncrementAttempt(ctx context.Context, id uuid.UUID) (int, error)
+	// Consume marks the code used, ErrInvalidCode if it already is.
+	Consume(ctx context.Context, id uuid.UUID, now time.Time) error
+	// Lock serializes callers holding the same key until the transaction ends.
+	Lock(ctx context.Context, key string) error
+	CountByDestination(ctx context.Context, dst verification.Destination, since time.Time) (int, error)
+	CountByIP(ctx context.Context, ip net.IP, since time.Time) (int, error)
+}
+
+// Message is what a Notifier delivers.
+type Message struct {
+	Purpose     verification.Purpose
+	Destination verification.Destination
+	Code        string
+	ExpiresAt   time.Time
+}
+
+// Notifier delivers codes (email, sms gateway, log sink...).
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+type UnitOfWork interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type Clock interface {
+	Now() time.Time
+}
@@ -0,0 +1,227 @@
+package verificationuc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/player"
+	"players_service/internal/domain/verification"
+)
+
+type Config struct {
+	Length         int
+	TTL            time.Duration
+	MaxAttempts    int           // wrong guesses allowed per code
+	ResendCooldown time.Duration // per purpose and destination
+	Window         time.Duration // rate limit window
+	MaxPerDest     int           // codes per destination per Window
+	MaxPerIP       int           // codes per IP per Window
+	Secret         []byte        // HMAC key for stored code hashes
+}
+
+func (c Config) withDefaults() Config {
+	if c.Length <= 0 {
+		c.Length = 6
+	}
+	if c.TTL <= 0 {
+		c.TTL = 10 * time.Minute
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 5
+	}
+	if c.ResendCooldown <= 0 {
+		c.ResendCooldown = time.Minute
+	}
+	if c.Window <= 0 {
+		c.Window = time.Hour
+	}
+	if c.MaxPerDest <= 0 {
+		c.MaxPerDest = 5
+	}
+	if c.MaxPerIP <= 0 {
+		c.MaxPerIP = 20
+	}
+	return c
+}
+
+type Service struct {
+	uow      UnitOfWork
+	codes    CodeRepository
+	notifier Notifier
+	clock    Clock
+	cfg      Config
+}
+
+func New(uow UnitOfWork, codes CodeRepository, notifier Notifier, clock Clock, cfg Config) *Service {
+	return &Service{
+		uow:      uow,
+		codes:    codes,
+		notifier: notifier,
+		clock:    clock,
+		cfg:      cfg.withDefaults(),
+	}
+}
+
+type SendResult struct {
+	CodeLength  int
+	ExpiresAt   time.Time
+	ResendAfter time.Time
+}
+
+// Send issues a new code for purpose to dst and delivers it. A new code
+// supersedes the previous one: only the latest code can be verified.
+func (s *Service) Send(ctx context.Context, purpose verification.Purpose, dst verification.Destination, ip net.IP) (SendResult, error) {
+	return s.send(ctx, purpose, dst, ip, true)
+}
+
+// Pretend is Send without the delivery: the limits are checked and a code is
+// stored all the same, so a destination with no account behind it is
+// throttled like, and answers like, one with an account.
+func (s *Service) Pretend(ctx context.Context, purpose verification.Purpose, dst verification.Destination, ip net.IP) (SendResult, error) {
+	return s.send(ctx, purpose, dst, ip, false)
+}
+
+func (s *Service) send(ctx context.Context, purpose verification.Purpose, dst verification.Destination, ip net.IP, deliver bool) (SendResult, error) {
+	now := s.clock.Now()
+
+	value, err := verification.GenerateDigits(s.cfg.Length)
+	if err != nil {
+		return SendResult{}, err
+	}
+
+	c := verification.Code{
+		ID:          uuid.New(),
+		Purpose:     purpose,
+		Destination: dst,
+		Hash:        s.hash(purpose, dst, value),
+		MaxAttempts: s.cfg.MaxAttempts,
+		IP:          ip,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.cfg.TTL),
+	}
+	// limits are counted and the code stored under locks on the destination
+	// and the IP, so that parallel sends cannot all pass the same count
+	err = s.uow.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.checkLimits(ctx, purpose, dst, ip, now); err != nil {
+			return err
+		}
+		return s.codes.Create(ctx, c)
+	})
+	if err != nil {
+		return SendResult{}, err
+	}
+
+	if deliver {
+		if err := s.notifier.Notify(ctx, Message{
+			Purpose:     purpose,
+			Destination: dst,
+			Code:        value,
+			ExpiresAt:   c.ExpiresAt,
+		}); err != nil {
+			return SendResult{}, err
+		}
+	}
+
+	return SendResult{
+		CodeLength:  s.cfg.Length,
+		ExpiresAt:   c.ExpiresAt,
+		ResendAfter: now.Add(s.cfg.ResendCooldown),
+	}, nil
+}
+
+// checkLimits must be called inside a transaction, which it locks the
+// destination and the IP for. The destination always goes first.
+func (s *Service) checkLimits(ctx context.Context, purpose verification.Purpose, dst verification.Destination, ip net.IP, now time.Time) error {
+	if err := s.codes.Lock(ctx, fmt.Sprintf("verification:dst:%d:%s", dst.Channel, dst.Value)); err != nil {
+		return err
+	}
+	if len(ip) > 0 {
+		if err := s.codes.Lock(ctx, "verification:ip:"+ip.String()); err != nil {
+			return err
+		}
+	}
+
+	last, err := s.codes.Latest(ctx, purpose, dst)
+	if err != nil && !errors.Is(err, player.ErrNotFound) {
+		return err
+	}
+	if err == nil && now.Before(last.CreatedAt.Add(s.cfg.ResendCooldown)) {
+		return fmt.Errorf("%w: resend cooldown", verification.ErrTooManyRequests)
+	}
+
+	since := now.Add(-s.cfg.Window)
+	n, err := s.codes.CountByDestination(ctx, dst, since)
+	if err != nil {
+		return err
+	}
+	if n >= s.cfg.MaxPerDest {
+		return fmt.Errorf("%w: %s limit", verification.ErrTooManyRequests, dst.Channel)
+	}
+
+	if len(ip) > 0 {
+		n, err := s.codes.CountByIP(ctx, ip, since)
+		if err != nil {
+			return err
+		}
+		if n >= s.cfg.MaxPerIP {
+			return fmt.Errorf("%w: ip limit", verification.ErrTooManyRequests)
+		}
+	}
+	return nil
+}
+
+// Verify checks value against the latest code sent for purpose to dst and
+// consumes it on success. Every guess is counted before the code is compared,
+// so parallel guesses cannot get past MaxAttempts.
+func (s *Service) Verify(ctx context.Context, purpose verification.Purpose, dst verification.Destination, value string) error {
+	id, err := s.Check(ctx, purpose, dst, value)
+	if err != nil {
+		return err
+	}
+	return s.Consume(ctx, id)
+}
+
+// Check is Verify without consuming: the guess is counted, and a matching
+// code stays usable until Consume is called with the id returned. Callers
+// check outside their transaction, so the guess counts even if they fail,
+// and consume inside it, so the code survives a rollback.
+func (s *Service) Check(ctx context.Context, purpose verification.Purpose, dst verification.Destination, value string) (uuid.UUID, error) {
+	c, err := s.codes.Latest(ctx, purpose, dst)
+	if err != nil {
+		if errors.Is(err, player.ErrNotFound) {
+			return uuid.Nil, verification.ErrInvalidCode
+		}
+		return uuid.Nil, err
+	}
+	if err := c.Usable(s.clock.Now()); err != nil {
+		return uuid.Nil, err
+	}
+	if _, err := s.codes.IncrementAttempt(ctx, c.ID); err != nil {
+		return uuid.Nil, err
+	}
+
+	if !hmac.Equal([]byte(s.hash(purpose, dst, value)), []byte(c.Hash)) {
+		return uuid.Nil, verification.ErrInvalidCode
+	}
+	return c.ID, nil
+}
+
+// Consume marks a code passed by Check as used, ErrInvalidCode if someone
+// else used it first.
+func (s *Service) Consume(ctx context.Context, id uuid.UUID) error {
+	return s.codes.Consume(ctx, id, s.clock.Now())
+}
+
+func (s *Service) hash(purpose verification.Purpose, dst verification.Destination, value string) string {
+	mac := hmac.New(sha256.New, s.cfg.Secret)
+	fmt.Fprintf(mac, "%s|%d|%s|%s", purpose, dst.Channel, dst.Value, value)
+	return hex.EncodeToString(mac.Sum(nil))
+}
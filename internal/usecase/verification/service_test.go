@@ -0,0 +1,399 @@
+package verificationuc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"players_service/internal/domain/player"
+	"players_service/internal/domain/verification"
+)
+
+// fakeTx holds the locks taken inside one fakeUoW transaction.
+type fakeTx struct {
+	held []*sync.Mutex
+}
+
+type txKey struct{}
+
+type fakeUoW struct{}
+
+func (fakeUoW) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*fakeTx); ok {
+		return fn(ctx)
+	}
+	tx := &fakeTx{}
+	defer func() {
+		for _, m := range tx.held {
+			m.Unlock()
+		}
+	}()
+	return fn(context.WithValue(ctx, txKey{}, tx))
+}
+
+type fakeCodes struct {
+	mu    sync.Mutex
+	codes []verification.Code
+	locks sync.Map // key -> *sync.Mutex
+}
+
+func (r *fakeCodes) Create(_ context.Context, c verification.Code) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.codes = append(r.codes, c)
+	return nil
+}
+
+func (r *fakeCodes) Latest(_ context.Context, purpose verification.Purpose, dst verification.Destination) (verification.Code, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.codes) - 1; i >= 0; i-- {
+		c := r.codes[i]
+		if c.Purpose == purpose && c.Destination == dst && c.ConsumedAt.IsZero() {
+			return c, nil
+		}
+	}
+	return verification.Code{}, player.ErrNotFound
+}
+
+func (r *fakeCodes) IncrementAttempt(_ context.Context, id uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.codes {
+		c := &r.codes[i]
+		if c.ID == id && c.ConsumedAt.IsZero() && c.Attempts < c.MaxAttempts {
+			c.Attempts++
+			return c.Attempts, nil
+		}
+	}
+	return 0, verification.ErrTooManyAttempts
+}
+
+func (r *fakeCodes) Consume(_ context.Context, id uuid.UUID, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.codes {
+		c := &r.codes[i]
+		if c.ID == id && c.ConsumedAt.IsZero() {
+			c.ConsumedAt = now
+			return nil
+		}
+	}
+	return verification.ErrInvalidCode
+}
+
+func (r *fakeCodes) Lock(ctx context.Context, key string) error {
+	tx, ok := ctx.Value(txKey{}).(*fakeTx)
+	if !ok {
+		return errors.New("lock outside of a transaction")
+	}
+	m, _ := r.locks.LoadOrStore(key, &sync.Mutex{})
+	m.(*sync.Mutex).Lock()
+	tx.held = append(tx.held, m.(*sync.Mutex))
+	return nil
+}
+
+func (r *fakeCodes) CountByDestination(_ context.Context, dst verification.Destination, since time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, c := range r.codes {
+		if c.Destination == dst && !c.CreatedAt.Before(since) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *fakeCodes) CountByIP(_ context.Context, ip net.IP, since time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, c := range r.codes {
+		if c.IP.Equal(ip) && !c.CreatedAt.Before(since) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *fakeCodes) attempts() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.codes[len(r.codes)-1].Attempts
+}
+
+type fakeNotifier struct {
+	mu   sync.Mutex
+	sent []Message
+}
+
+func (n *fakeNotifier) Notify(_ context.Context, msg Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, msg)
+	return nil
+}
+
+func (n *fakeNotifier) last() Message {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.sent[len(n.sent)-1]
+}
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestService(cfg Config) (*Service, *fakeCodes, *fakeNotifier, *fakeClock) {
+	codes := &fakeCodes{}
+	n := &fakeNotifier{}
+	clk := &fakeClock{now: time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)}
+	cfg.Secret = []byte("secret")
+	return New(fakeUoW{}, codes, n, clk, cfg), codes, n, clk
+}
+
+var (
+	testDst = verification.Destination{Channel: verification.ChannelEmail, Value: "p@example.com"}
+	testIP  = net.ParseIP("203.0.113.7")
+)
+
+func wrongCode(code string) string {
+	if code[0] == '0' {
+		return "1" + code[1:]
+	}
+	return "0" + code[1:]
+}
+
+func TestVerifyConsumesCode(t *testing.T) {
+	s, _, n, _ := newTestService(Config{})
+	ctx := context.Background()
+
+	if _, err := s.Send(ctx, verification.PurposeVerify, testDst, testIP); err != nil {
+		t.Fatal(err)
+	}
+	code := n.last().Code
+
+	if err := s.Verify(ctx, verification.PurposeVerify, testDst, code); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if err := s.Verify(ctx, verification.PurposeVerify, testDst, code); !errors.Is(err, verification.ErrInvalidCode) {
+		t.Fatalf("reused code: want ErrInvalidCode, got %v", err)
+	}
+}
+
+func TestCheckLeavesCodeToConsume(t *testing.T) {
+	s, _, n, _ := newTestService(Config{})
+	ctx := context.Background()
+
+	if _, err := s.Send(ctx, verification.PurposeRegistration, testDst, testIP); err != nil {
+		t.Fatal(err)
+	}
+	code := n.last().Code
+
+	if _, err := s.Check(ctx, verification.PurposeRegistration, testDst, wrongCode(code)); !errors.Is(err, verification.ErrInvalidCode) {
+		t.Fatalf("wrong code: want ErrInvalidCode, got %v", err)
+	}
+	id, err := s.Check(ctx, verification.PurposeRegistration, testDst, code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// not consumed yet, e.g. the caller's transaction rolled back
+	if _, err := s.Check(ctx, verification.PurposeRegistration, testDst, code); err != nil {
+		t.Fatalf("second check: %v", err)
+	}
+	if err := s.Consume(ctx, id); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Consume(ctx, id); !errors.Is(err, verification.ErrInvalidCode) {
+		t.Fatalf("consumed twice: want ErrInvalidCode, got %v", err)
+	}
+	if _, err := s.Check(ctx, verification.PurposeRegistration, testDst, code); !errors.Is(err, verification.ErrInvalidCode) {
+		t.Fatalf("check after consume: want ErrInvalidCode, got %v", err)
+	}
+}
+
+func TestVerifyPurposeScoped(t *testing.T) {
+	s, _, n, _ := newTestService(Config{})
+	ctx := context.Background()
+
+	if _, err := s.Send(ctx, verification.PurposeRegistration, testDst, testIP); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Verify(ctx, verification.PurposeReset, testDst, n.last().Code); !errors.Is(err, verification.ErrInvalidCode) {
+		t.Fatalf("want ErrInvalidCode, got %v", err)
+	}
+}
+
+func TestVerifyAttemptLimit(t *testing.T) {
+	s, codes, n, _ := newTestService(Config{MaxAttempts: 3})
+	ctx := context.Background()
+
+	if _, err := s.Send(ctx, verification.PurposeVerify, testDst, testIP); err != nil {
+		t.Fatal(err)
+	}
+	code := n.last().Code
+
+	for i := 0; i < 3; i++ {
+		if err := s.Verify(ctx, verification.PurposeVerify, testDst, wrongCode(code)); !errors.Is(err, verification.ErrInvalidCode) {
+			t.Fatalf("guess %d: want ErrInvalidCode, got %v", i+1, err)
+		}
+	}
+	// the right code no longer helps
+	if err := s.Verify(ctx, verification.PurposeVerify, testDst, code); !errors.Is(err, verification.ErrTooManyAttempts) {
+		t.Fatalf("want ErrTooManyAttempts, got %v", err)
+	}
+	if got := codes.attempts(); got != 3 {
+		t.Fatalf("attempts = %d, want 3", got)
+	}
+}
+
+func TestVerifyParallelGuessesStopAtLimit(t *testing.T) {
+	const maxAttempts = 5
+	s, codes, n, _ := newTestService(Config{MaxAttempts: maxAttempts})
+	ctx := context.Background()
+
+	if _, err := s.Send(ctx, verification.PurposeVerify, testDst, testIP); err != nil {
+		t.Fatal(err)
+	}
+	wrong := wrongCode(n.last().Code)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		compared int
+	)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := s.Verify(ctx, verification.PurposeVerify, testDst, wrong)
+			if errors.Is(err, verification.ErrInvalidCode) {
+				mu.Lock()
+				compared++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if compared != maxAttempts {
+		t.Fatalf("%d guesses compared, want %d", compared, maxAttempts)
+	}
+	if got := codes.attempts(); got != maxAttempts {
+		t.Fatalf("attempts = %d, want %d", got, maxAttempts)
+	}
+}
+
+func TestVerifyExpired(t *testing.T) {
+	s, _, n, clk := newTestService(Config{TTL: time.Minute})
+	ctx := context.Background()
+
+	if _, err := s.Send(ctx, verification.PurposeVerify, testDst, testIP); err != nil {
+		t.Fatal(err)
+	}
+	clk.Advance(time.Minute)
+	if err := s.Verify(ctx, verification.PurposeVerify, testDst, n.last().Code); !errors.Is(err, verification.ErrCodeExpired) {
+		t.Fatalf("want ErrCodeExpired, got %v", err)
+	}
+}
+
+func TestSendLimits(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("resend cooldown", func(t *testing.T) {
+		s, _, _, clk := newTestService(Config{ResendCooldown: time.Minute})
+		if _, err := s.Send(ctx, verification.PurposeVerify, testDst, testIP); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Send(ctx, verification.PurposeVerify, testDst, testIP); !errors.Is(err, verification.ErrTooManyRequests) {
+			t.Fatalf("want ErrTooManyRequests, got %v", err)
+		}
+		clk.Advance(time.Minute)
+		if _, err := s.Send(ctx, verification.PurposeVerify, testDst, testIP); err != nil {
+			t.Fatalf("after cooldown: %v", err)
+		}
+	})
+
+	t.Run("per destination", func(t *testing.T) {
+		s, _, _, clk := newTestService(Config{ResendCooldown: time.Second, MaxPerDest: 2, Window: time.Hour})
+		for i := 0; i < 2; i++ {
+			if _, err := s.Send(ctx, verification.PurposeVerify, testDst, testIP); err != nil {
+				t.Fatal(err)
+			}
+			clk.Advance(time.Second)
+		}
+		if _, err := s.Send(ctx, verification.PurposeReset, testDst, testIP); !errors.Is(err, verification.ErrTooManyRequests) {
+			t.Fatalf("want ErrTooManyRequests, got %v", err)
+		}
+	})
+
+	t.Run("per ip", func(t *testing.T) {
+		s, _, _, _ := newTestService(Config{MaxPerIP: 2, Window: time.Hour})
+		for i, email := range []string{"a@example.com", "b@example.com"} {
+			dst := verification.Destination{Channel: verification.ChannelEmail, Value: email}
+			if _, err := s.Send(ctx, verification.PurposeVerify, dst, testIP); err != nil {
+				t.Fatalf("send %d: %v", i+1, err)
+			}
+		}
+		dst := verification.Destination{Channel: verification.ChannelEmail, Value: "c@example.com"}
+		if _, err := s.Send(ctx, verification.PurposeVerify, dst, testIP); !errors.Is(err, verification.ErrTooManyRequests) {
+			t.Fatalf("want ErrTooManyRequests, got %v", err)
+		}
+	})
+}
+
+func TestSendParallelRespectsLimits(t *testing.T) {
+	s, _, n, _ := newTestService(Config{ResendCooldown: time.Minute})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = s.Send(ctx, verification.PurposeVerify, testDst, testIP)
+		}()
+	}
+	wg.Wait()
+
+	if len(n.sent) != 1 {
+		t.Fatalf("%d codes sent within the cooldown, want 1", len(n.sent))
+	}
+}
+
+func TestPretendCountsWithoutDelivering(t *testing.T) {
+	s, codes, n, _ := newTestService(Config{MaxPerDest: 1})
+	ctx := context.Background()
+
+	if _, err := s.Pretend(ctx, verification.PurposeReset, testDst, testIP); err != nil {
+		t.Fatal(err)
+	}
+	if len(n.sent) != 0 {
+		t.Fatal("Pretend delivered a code")
+	}
+	if len(codes.codes) != 1 {
+		t.Fatalf("%d codes stored, want 1", len(codes.codes))
+	}
+	if _, err := s.Pretend(ctx, verification.PurposeReset, testDst, testIP); !errors.Is(err, verification.ErrTooManyRequests) {
+		t.Fatalf("want ErrTooManyRequests, got %v", err)
+	}
+}
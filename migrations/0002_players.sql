@@ -56,3 +56,8 @@ CREATE TABLE IF NOT EXISTS outbox (
 );
 
 CREATE INDEX IF NOT EXISTS idx_outbox_published_at ON outbox(published_at);
+
+-- +migrate Down
+DROP TABLE IF EXISTS outbox;
+DROP TABLE IF EXISTS player_status_events;
+DROP TABLE IF EXISTS players;
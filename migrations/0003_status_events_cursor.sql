@@ -0,0 +1,10 @@
+-- keyset pagination over player status history: (created_at, id) DESC
+CREATE INDEX IF NOT EXISTS idx_pse_player_id_created_at_id
+  ON player_status_events(player_id, created_at DESC, id DESC);
+
+DROP INDEX IF EXISTS idx_pse_player_id_created_at;
+
+-- +migrate Down
+CREATE INDEX IF NOT EXISTS idx_pse_player_id_created_at ON player_status_events(player_id, created_at DESC);
+
+DROP INDEX IF EXISTS idx_pse_player_id_created_at_id;
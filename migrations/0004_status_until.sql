@@ -0,0 +1,13 @@
+-- temporary blocks / freezes
+ALTER TABLE players ADD COLUMN IF NOT EXISTS status_until TIMESTAMPTZ NULL;
+
+CREATE INDEX IF NOT EXISTS idx_players_status_until
+  ON players(status_until) WHERE status_until IS NOT NULL;
+
+ALTER TABLE player_status_events ADD COLUMN IF NOT EXISTS until TIMESTAMPTZ NULL;
+
+-- +migrate Down
+ALTER TABLE player_status_events DROP COLUMN IF EXISTS until;
+
+DROP INDEX IF EXISTS idx_players_status_until;
+ALTER TABLE players DROP COLUMN IF EXISTS status_until;
@@ -0,0 +1,19 @@
+-- outbox relay bookkeeping
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS attempts        INT NOT NULL DEFAULT 0;
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ NULL;
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS last_error      TEXT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending
+  ON outbox(created_at) WHERE published_at IS NULL;
+
+-- relay keeps per-key order by skipping keys with an earlier message in backoff
+CREATE INDEX IF NOT EXISTS idx_outbox_key_pending
+  ON outbox(key, created_at) WHERE published_at IS NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_outbox_key_pending;
+DROP INDEX IF EXISTS idx_outbox_pending;
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS last_error;
+ALTER TABLE outbox DROP COLUMN IF EXISTS next_attempt_at;
+ALTER TABLE outbox DROP COLUMN IF EXISTS attempts;
@@ -0,0 +1,13 @@
+-- password credentials (argon2id PHC strings)
+CREATE TABLE IF NOT EXISTS player_passwords (
+  player_id  UUID PRIMARY KEY REFERENCES players(id) ON DELETE CASCADE,
+  hash       TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_players_phone ON players(phone);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_players_phone;
+DROP TABLE IF EXISTS player_passwords;
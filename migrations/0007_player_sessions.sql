@@ -0,0 +1,20 @@
+-- server-side sessions, one row per issued refresh token
+CREATE TABLE IF NOT EXISTS player_sessions (
+  id            UUID PRIMARY KEY,
+  player_id     UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+  family_id     UUID NOT NULL,
+  refresh_hash  TEXT NOT NULL UNIQUE,
+  ip            INET NULL,
+  user_agent    TEXT NULL,
+  created_at    TIMESTAMPTZ NOT NULL,
+  expires_at    TIMESTAMPTZ NOT NULL,
+  revoked_at    TIMESTAMPTZ NULL,
+  revoke_reason TEXT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_sessions_player_active
+  ON player_sessions(player_id) WHERE revoked_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_player_sessions_family ON player_sessions(family_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS player_sessions;
@@ -0,0 +1,22 @@
+-- one-time verification codes (hmac of the value only)
+CREATE TABLE IF NOT EXISTS verification_codes (
+  id           UUID PRIMARY KEY,
+  purpose      TEXT NOT NULL,
+  channel      SMALLINT NOT NULL,
+  destination  TEXT NOT NULL,
+  code_hash    TEXT NOT NULL,
+  attempts     INT NOT NULL DEFAULT 0,
+  max_attempts INT NOT NULL,
+  ip           INET NULL,
+  created_at   TIMESTAMPTZ NOT NULL,
+  expires_at   TIMESTAMPTZ NOT NULL,
+  consumed_at  TIMESTAMPTZ NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_verification_codes_dest
+  ON verification_codes(channel, destination, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_verification_codes_ip
+  ON verification_codes(ip, created_at DESC);
+
+-- +migrate Down
+DROP TABLE IF EXISTS verification_codes;
@@ -0,0 +1,9 @@
+-- confirmation of player contacts, null until verified
+ALTER TABLE players
+  ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ NULL,
+  ADD COLUMN IF NOT EXISTS phone_verified_at TIMESTAMPTZ NULL;
+
+-- +migrate Down
+ALTER TABLE players
+  DROP COLUMN IF EXISTS phone_verified_at,
+  DROP COLUMN IF EXISTS email_verified_at;
@@ -0,0 +1,28 @@
+-- single-use password reset tokens (sha256 of the token only)
+CREATE TABLE IF NOT EXISTS password_resets (
+  id         UUID PRIMARY KEY,
+  player_id  UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+  token_hash TEXT NOT NULL UNIQUE,
+  ip         INET NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  used_at    TIMESTAMPTZ NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_resets_player ON password_resets(player_id);
+
+-- who reset or changed a password, and from where
+CREATE TABLE IF NOT EXISTS password_audit (
+  id         UUID PRIMARY KEY,
+  player_id  UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+  action     TEXT NOT NULL,
+  ip         INET NULL,
+  created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_audit_player
+  ON password_audit(player_id, created_at DESC);
+
+-- +migrate Down
+DROP TABLE IF EXISTS password_audit;
+DROP TABLE IF EXISTS password_resets;
@@ -0,0 +1,13 @@
+-- external identities (oauth2 / openid providers) linked to players
+CREATE TABLE IF NOT EXISTS social_accounts (
+  provider  TEXT NOT NULL,
+  subject   TEXT NOT NULL,
+  player_id UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+  email     TEXT NULL,
+  linked_at TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (provider, subject),
+  UNIQUE (player_id, provider)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS social_accounts;
@@ -0,0 +1,50 @@
+-- emails and phones of players, each value unique across all players;
+-- the primary one of each kind mirrors players.email / players.phone
+CREATE TABLE IF NOT EXISTS player_credentials (
+  id          UUID PRIMARY KEY,
+  player_id   UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+  kind        SMALLINT NOT NULL,
+  value       TEXT NOT NULL,
+  is_primary  BOOLEAN NOT NULL DEFAULT FALSE,
+  verified_at TIMESTAMPTZ NULL,
+  created_at  TIMESTAMPTZ NOT NULL,
+  UNIQUE (kind, value)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_player_credentials_primary
+  ON player_credentials(player_id, kind) WHERE is_primary;
+
+-- phones used to be shared, credentials are not. Duplicates are not resolved
+-- here: the migration stops and lists the players involved, support decides
+-- who keeps the phone, and the migration is run again.
+DO $$
+DECLARE
+  dups TEXT;
+BEGIN
+  SELECT string_agg(ids, '; ')
+    INTO dups
+    FROM (
+      SELECT string_agg(id::text, ', ' ORDER BY created_at, id) AS ids
+        FROM players
+       WHERE phone IS NOT NULL
+       GROUP BY phone
+      HAVING count(*) > 1
+    ) d;
+  IF dups IS NOT NULL THEN
+    RAISE EXCEPTION 'players sharing a phone, clear it on all but one of each group: %', dups;
+  END IF;
+END $$;
+
+INSERT INTO player_credentials (id, player_id, kind, value, is_primary, verified_at, created_at)
+SELECT md5(id::text || ':email')::uuid, id, 1, email, TRUE, email_verified_at, created_at
+  FROM players
+ON CONFLICT DO NOTHING;
+
+INSERT INTO player_credentials (id, player_id, kind, value, is_primary, verified_at, created_at)
+SELECT md5(id::text || ':phone')::uuid, id, 2, phone, TRUE, phone_verified_at, created_at
+  FROM players
+ WHERE phone IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+-- +migrate Down
+DROP TABLE IF EXISTS player_credentials;
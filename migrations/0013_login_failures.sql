@@ -0,0 +1,15 @@
+-- failed password checks, counted in a sliding window for lockouts
+CREATE TABLE IF NOT EXISTS login_failures (
+  id         BIGSERIAL PRIMARY KEY,
+  player_id  UUID NULL REFERENCES players(id) ON DELETE CASCADE,
+  ip         INET NULL,
+  created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_failures_player
+  ON login_failures(player_id, created_at) WHERE player_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_login_failures_ip
+  ON login_failures(ip, created_at) WHERE ip IS NOT NULL;
+
+-- +migrate Down
+DROP TABLE IF EXISTS login_failures;
@@ -0,0 +1,18 @@
+-- login history, successful and failed attempts of known players
+CREATE TABLE IF NOT EXISTS player_logins (
+  id         UUID PRIMARY KEY,
+  player_id  UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+  ip         INET NULL,
+  user_agent TEXT NULL,
+  method     TEXT NOT NULL,
+  outcome    TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_logins_player_created
+  ON player_logins(player_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_player_logins_ip
+  ON player_logins(ip) WHERE ip IS NOT NULL;
+
+-- +migrate Down
+DROP TABLE IF EXISTS player_logins;
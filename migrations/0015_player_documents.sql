@@ -0,0 +1,33 @@
+-- KYC documents and the audit trail of their reviews
+CREATE TABLE IF NOT EXISTS player_documents (
+  id         UUID PRIMARY KEY,
+  player_id  UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+  type       SMALLINT NOT NULL,
+  file_ref   TEXT NOT NULL,
+  status     SMALLINT NOT NULL,
+  reason     TEXT NULL,
+  version    BIGINT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL,
+  updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_documents_player_created
+  ON player_documents(player_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS player_document_reviews (
+  id          UUID PRIMARY KEY,
+  document_id UUID NOT NULL REFERENCES player_documents(id) ON DELETE CASCADE,
+  player_id   UUID NOT NULL REFERENCES players(id) ON DELETE CASCADE,
+  from_status SMALLINT NOT NULL,
+  to_status   SMALLINT NOT NULL,
+  reason      TEXT NULL,
+  actor_type  SMALLINT NOT NULL,
+  created_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_player_document_reviews_document
+  ON player_document_reviews(document_id, created_at DESC);
+
+-- +migrate Down
+DROP TABLE IF EXISTS player_document_reviews;
+DROP TABLE IF EXISTS player_documents;
@@ -0,0 +1,10 @@
+-- KYC level derived from approved documents, 0 = none
+ALTER TABLE players
+  ADD COLUMN IF NOT EXISTS kyc_level SMALLINT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_players_kyc_level ON players(kyc_level);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_players_kyc_level;
+ALTER TABLE players
+  DROP COLUMN IF EXISTS kyc_level;
@@ -0,0 +1,7 @@
+-- country the registration IP geolocated to, null when unknown
+ALTER TABLE players
+  ADD COLUMN IF NOT EXISTS registration_ip_country TEXT NULL;
+
+-- +migrate Down
+ALTER TABLE players
+  DROP COLUMN IF EXISTS registration_ip_country;
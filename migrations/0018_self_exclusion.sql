@@ -0,0 +1,9 @@
+-- responsible gambling self-exclusion, 0 = none; excluded_until is null for permanent
+ALTER TABLE players
+  ADD COLUMN IF NOT EXISTS exclusion_kind SMALLINT NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS excluded_until TIMESTAMPTZ NULL;
+
+-- +migrate Down
+ALTER TABLE players
+  DROP COLUMN IF EXISTS excluded_until,
+  DROP COLUMN IF EXISTS exclusion_kind;
@@ -0,0 +1,5 @@
+-- relay leases claimed messages instead of holding row locks while publishing
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ NULL;
+
+-- +migrate Down
+ALTER TABLE outbox DROP COLUMN IF EXISTS locked_until;
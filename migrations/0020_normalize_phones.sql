@@ -0,0 +1,43 @@
+-- phones are stored in E.164 ("+" and 7 to 15 digits) so one number written
+-- in different ways takes a single slot in UNIQUE(kind, value). Before, only
+-- surrounding spaces were trimmed. Like 0012, values that cannot be converted
+-- or that collide once converted stop the migration and list the players
+-- involved, support fixes them and the migration is run again.
+DO $$
+DECLARE
+  bad TEXT;
+BEGIN
+  SELECT string_agg(player_id::text || ' ' || value, ', ' ORDER BY player_id, value)
+    INTO bad
+    FROM player_credentials
+   WHERE kind = 2
+     AND '+' || regexp_replace(btrim(value), '^(\+|00)', '') !~ '^\+[1-9][0-9]{6,14}$';
+  IF bad IS NOT NULL THEN
+    RAISE EXCEPTION 'phones not convertible to E.164, change or remove them: %', bad;
+  END IF;
+
+  SELECT string_agg(ids, '; ')
+    INTO bad
+    FROM (
+      SELECT string_agg(player_id::text, ', ' ORDER BY created_at, player_id) AS ids
+        FROM player_credentials
+       WHERE kind = 2
+       GROUP BY '+' || regexp_replace(btrim(value), '^(\+|00)', '')
+      HAVING count(*) > 1
+    ) d;
+  IF bad IS NOT NULL THEN
+    RAISE EXCEPTION 'players sharing a phone once normalized, remove it from all but one of each group: %', bad;
+  END IF;
+END $$;
+
+UPDATE player_credentials
+   SET value = '+' || regexp_replace(btrim(value), '^(\+|00)', '')
+ WHERE kind = 2;
+
+UPDATE players
+   SET phone = '+' || regexp_replace(btrim(phone), '^(\+|00)', '')
+ WHERE phone IS NOT NULL;
+
+-- +migrate Down
+-- the original spelling of the numbers is not kept, E.164 values are valid
+-- for the older code as well
@@ -0,0 +1,11 @@
+// Package migrations bundles the SQL schema migrations into the binary.
+//
+// Files are named NNNN_description.sql and applied in version order. Everything
+// above a "-- +migrate Down" line is the up migration, everything below it the
+// down migration.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS